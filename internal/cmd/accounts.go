@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"sort"
+	"strings"
 
 	"github.com/bcmister/qs/internal/config"
 	"github.com/bcmister/qs/internal/tui"
@@ -16,6 +19,40 @@ var accountsCmd = &cobra.Command{
 	RunE:  runAccounts,
 }
 
+var accountsListOutput outputOptions
+
+var accountsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured accounts",
+	Long: `List configured accounts.
+
+--json, --tsv and --format print them for other tools. TSV columns are id,
+label, command, enabled and installed. API keys are reported by name only;
+their values are never printed.`,
+	Args: cobra.NoArgs,
+	RunE: runAccountsList,
+}
+
+func init() {
+	addOutputFlags(accountsListCmd, &accountsListOutput)
+	accountsCmd.AddCommand(accountsListCmd)
+}
+
+// accountInfo is one account as qs accounts list reports it. It holds key
+// names, never values, so no output format can reveal a secret.
+type accountInfo struct {
+	ID        string   `json:"id"`
+	Label     string   `json:"label"`
+	Command   string   `json:"command"`
+	Args      []string `json:"args"`
+	Enabled   bool     `json:"enabled"`
+	Default   bool     `json:"default"`
+	Installed bool     `json:"installed"` // the command is on PATH
+	AuthUser  string   `json:"authUser,omitempty"`
+	Prompt    bool     `json:"prompt"` // takes an initial prompt
+	Keys      []string `json:"keys"`   // names of the user's keys, as `qs keys list` shows them
+}
+
 func runAccounts(cmd *cobra.Command, args []string) error {
 	cfg, err := config.Load("")
 	if err != nil {
@@ -29,8 +66,89 @@ func runAccounts(cmd *cobra.Command, args []string) error {
 	}
 
 	config.EnsureDefaults(cfg)
-	accounts := tui.NewAccounts(cfg)
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	accounts := tui.NewAccounts(cfg, keys)
 	p := tea.NewProgram(accounts, tea.WithAltScreen())
 	_, err = p.Run()
 	return err
 }
+
+func runAccountsList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	// Key names are left out when the keys file is encrypted, rather than
+	// prompting for the passphrase in a listing
+	var keys config.AccountKeys
+	encrypted, err := config.KeysEncrypted()
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		if keys, err = config.LoadKeys(); err != nil {
+			return err
+		}
+	}
+
+	accounts := listAccountInfo(cfg, keys)
+	if accountsListOutput.structured() {
+		return writeList(cmd.OutOrStdout(), accountsListOutput, accounts, func(a accountInfo) []string {
+			return []string{a.ID, a.Label, a.Command, fmt.Sprint(a.Enabled), fmt.Sprint(a.Installed)}
+		})
+	}
+
+	fmt.Println()
+	for _, a := range accounts {
+		var meta []string
+		if a.Default {
+			meta = append(meta, "default")
+		}
+		if !a.Enabled {
+			meta = append(meta, "disabled")
+		}
+		if !a.Installed {
+			meta = append(meta, "not installed")
+		}
+		if len(a.Keys) > 0 {
+			meta = append(meta, "keys: "+strings.Join(a.Keys, ", "))
+		}
+		fmt.Printf("  %s %s  %s\n",
+			tui.TitleStyle.Render("◆"),
+			tui.WhiteStyle.Render(a.ID),
+			tui.DimStyle.Render(strings.Join(append([]string{a.Label}, meta...), " · ")))
+		fmt.Printf("    %s\n", tui.DimStyle.Render(strings.Join(append([]string{a.Command}, a.Args...), " ")))
+	}
+	fmt.Println()
+	return nil
+}
+
+// listAccountInfo describes every configured account.
+func listAccountInfo(cfg *config.Config, keys config.AccountKeys) []accountInfo {
+	var infos []accountInfo
+	for _, a := range cfg.Accounts {
+		_, lookErr := exec.LookPath(a.Command)
+		keyNames := []string{}
+		for name := range config.UserAPIKeys(keys, a.ID) {
+			keyNames = append(keyNames, name)
+		}
+		sort.Strings(keyNames)
+		infos = append(infos, accountInfo{
+			ID:        a.ID,
+			Label:     a.Label,
+			Command:   a.Command,
+			Args:      a.ResolvedArgs(),
+			Enabled:   a.Enabled,
+			Default:   a.ID == cfg.DefaultAccount,
+			Installed: lookErr == nil,
+			AuthUser:  a.AuthUser,
+			Prompt:    a.SupportsPrompt(),
+			Keys:      keyNames,
+		})
+	}
+	return infos
+}
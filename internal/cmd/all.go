@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/bcmister/qs/internal/config"
@@ -12,18 +14,37 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var profileFlag string
+
 var allCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Launch terminals across all monitors",
 	RunE:  runAll,
 }
 
+func init() {
+	allCmd.Flags().StringVar(&profileFlag, "profile", "", "Launch a named profile instead of the default monitor arrangement")
+	allCmd.RegisterFlagCompletionFunc("profile", completeProfileFlag)
+}
+
 func runAll(cmd *cobra.Command, args []string) error {
+	return launchAll(profileFlag)
+}
+
+// launchAll runs `qs all`, optionally with a named profile's arrangement in
+// place of the top-level monitors config.
+func launchAll(profile string) error {
 	cfg, err := config.Load("")
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if profile != "" {
+		if err := cfg.UseProfile(profile); err != nil {
+			return err
+		}
+	}
+
 	if strings.TrimSpace(cfg.ProjectsRoot) == "" {
 		return fmt.Errorf("projects root not configured — run qs setup first")
 	}
@@ -33,7 +54,20 @@ func runAll(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	cfg.ProjectsRoot = projectsRoot
+	cfg.SetProjectsRoot(projectsRoot)
+
+	term, err := launcher.New(cfg.Terminal)
+	if err != nil {
+		return err
+	}
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+
+	if term.Name() == "tmux" {
+		return runAllTmux(cfg, keys, tmuxAllSessionName(profile))
+	}
 
 	// Run the AllModel TUI to get window counts per monitor
 	allModel := tui.NewAll(cfg)
@@ -52,7 +86,7 @@ func runAll(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	windowCounts := result.WindowCounts()
+	assigned := result.Windows()
 
 	// Detect monitors for positioning
 	monitors, err := monitor.Detect()
@@ -60,13 +94,15 @@ func runAll(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect monitors: %w", err)
 	}
 
-	// Build LaunchConfigs — each window runs plain `qs` with its own picker
+	// Build LaunchConfigs — assigned windows launch their account directly,
+	// the rest run plain `qs` with their own picker
 	var configs []launcher.LaunchConfig
 	for monIdx, mon := range monitors {
-		count := 1
-		if monIdx < len(windowCounts) {
-			count = windowCounts[monIdx]
+		windows := []config.WindowConfig{{}}
+		if monIdx < len(assigned) {
+			windows = assigned[monIdx]
 		}
+		count := len(windows)
 		if count < 1 {
 			continue
 		}
@@ -75,15 +111,10 @@ func runAll(cmd *cobra.Command, args []string) error {
 		positions := launcher.CalculateLayout(&mon, count, layout)
 
 		for winIdx, pos := range positions {
-			configs = append(configs, launcher.LaunchConfig{
-				Title:      fmt.Sprintf("qs-%d-%d", monIdx+1, winIdx+1),
-				WorkingDir: cfg.ProjectsRoot,
-				X:          pos.X,
-				Y:          pos.Y,
-				Width:      pos.Width,
-				Height:     pos.Height,
-				Command:    "qs",
-			})
+			lc := windowLaunchConfig(cfg, keys, monIdx, winIdx, windows[winIdx])
+			lc.X, lc.Y, lc.Width, lc.Height = pos.X, pos.Y, pos.Width, pos.Height
+			configs = append(configs, lc)
+			recordWindowLaunch(windows[winIdx], lc)
 		}
 	}
 
@@ -91,8 +122,147 @@ func runAll(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no windows to launch")
 	}
 
-	// Launch all windows as new terminals with their own pickers
-	launcher.LaunchAll(configs)
+	launcher.LaunchAll(term, configs)
 
 	return nil
 }
+
+// tmuxAllSession is the tmux session `qs all` builds when the terminal
+// backend is tmux. Profiles get their own session, suffixed with the name.
+const tmuxAllSession = "qs-all"
+
+// tmuxAllSessionName returns the session `qs all` uses for profile ("" for
+// none). tmux reads '.' and ':' in targets as window and pane separators,
+// so they are replaced in names saved before profile names excluded them.
+func tmuxAllSessionName(profile string) string {
+	if profile == "" {
+		return tmuxAllSession
+	}
+	return tmuxAllSession + "-" + strings.NewReplacer(".", "_", ":", "_").Replace(profile)
+}
+
+// runAllTmux builds a tmux session with one window per configured monitor,
+// split into one pane per configured window, then attaches to it. If the
+// session is already running it is attached as-is rather than rebuilt.
+func runAllTmux(cfg *config.Config, keys config.AccountKeys, session string) error {
+	if !launcher.TmuxSessionExists(session) {
+		windows := tmuxWindows(cfg, keys)
+		if len(windows) == 0 {
+			return fmt.Errorf("no windows to launch")
+		}
+		if err := launcher.LaunchTmuxSession(session, windows); err != nil {
+			return err
+		}
+	}
+	return launcher.AttachTmuxSession(session)
+}
+
+// tmuxWindows maps the configured monitors to tmux windows. Pane counts
+// follow the same rules as CalculateLayout: a "full" monitor gets a single
+// pane and monitors with no windows are skipped.
+func tmuxWindows(cfg *config.Config, keys config.AccountKeys) []launcher.TmuxWindow {
+	var windows []launcher.TmuxWindow
+	for monIdx, mc := range cfg.Monitors {
+		count := mc.WindowCount()
+		if mc.Layout == "full" && count > 1 {
+			count = 1
+		}
+		if count < 1 {
+			continue
+		}
+
+		win := launcher.TmuxWindow{
+			Name:   fmt.Sprintf("monitor-%d", monIdx+1),
+			Layout: mc.Layout,
+		}
+		for winIdx := 0; winIdx < count; winIdx++ {
+			lc := windowLaunchConfig(cfg, keys, monIdx, winIdx, mc.WindowAt(winIdx))
+			win.Panes = append(win.Panes, lc)
+			recordWindowLaunch(mc.WindowAt(winIdx), lc)
+		}
+		windows = append(windows, win)
+	}
+	return windows
+}
+
+// windowLaunchConfig returns what one `qs all` window runs: the window's
+// account launched directly in its project when one is assigned, otherwise
+// the picker in the projects root. An assignment that can't be resolved is
+// reported on stderr and falls back to the picker.
+func windowLaunchConfig(cfg *config.Config, keys config.AccountKeys, monIdx, winIdx int, win config.WindowConfig) launcher.LaunchConfig {
+	lc := launcher.LaunchConfig{
+		Title:      win.Title,
+		WorkingDir: cfg.ProjectsRoot,
+		Command:    "qs",
+	}
+	if lc.Title == "" {
+		lc.Title = fmt.Sprintf("qs-%d-%d", monIdx+1, winIdx+1)
+	}
+	if win.Project == "" {
+		return lc
+	}
+
+	dir, err := windowProjectDir(cfg, win.Project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  monitor %d window %d: %v, opening the picker instead\n", monIdx+1, winIdx+1, err)
+		return lc
+	}
+	tool := win.Tool
+	if tool == "" {
+		tool = "claude"
+	}
+	account := config.AccountByID(cfg.Accounts, tool)
+	if account == nil {
+		fmt.Fprintf(os.Stderr, "  monitor %d window %d: account %q not found, opening the picker instead\n", monIdx+1, winIdx+1, tool)
+		return lc
+	}
+	if !account.Enabled {
+		fmt.Fprintf(os.Stderr, "  monitor %d window %d: account %q is disabled, opening the picker instead\n", monIdx+1, winIdx+1, tool)
+		return lc
+	}
+
+	vars, err := config.LaunchEnv(cfg, keys, account.ID, dir)
+	var env map[string]string
+	if err == nil {
+		env, err = config.ResolveEnv(cfg, vars)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  monitor %d window %d: %v, opening the picker instead\n", monIdx+1, winIdx+1, err)
+		return lc
+	}
+
+	lc.Env = config.BuildEnv(os.Environ(), account.Env, env)
+	lc.WorkingDir = dir
+	lc.Command = account.Command
+	lc.Args = append(account.ResolvedArgs(), win.Args...)
+	return lc
+}
+
+// windowProjectDir resolves a window's project the way --project does, as
+// root:name, a folder in any root or a favorite. Absolute paths are used
+// as they are.
+func windowProjectDir(cfg *config.Config, project string) (string, error) {
+	if !filepath.IsAbs(project) {
+		return cfg.ResolveProject(project)
+	}
+	dir := filepath.Clean(project)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("project %q not found", project)
+	}
+	return dir, nil
+}
+
+// recordWindowLaunch adds a window that launches its account directly to the
+// history. Windows running the picker record their own launch.
+func recordWindowLaunch(win config.WindowConfig, lc launcher.LaunchConfig) {
+	if lc.Command == "qs" {
+		return
+	}
+	tool := win.Tool
+	if tool == "" {
+		tool = "claude"
+	}
+	if err := config.RecordLaunch(lc.WorkingDir, tool); err != nil {
+		fmt.Fprintf(os.Stderr, "  %s %v\n", tui.WarningStyle.Render("!"), err)
+	}
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Print a shell completion script",
+	Long: `Print a completion script for your shell. Projects, accounts, roots and
+profiles complete from your config.
+
+Bash:
+  source <(qs completion bash)
+  # or permanently: qs completion bash > /etc/bash_completion.d/qs
+
+Zsh:
+  qs completion zsh > "${fpath[1]}/_qs"
+  # completion must be enabled: autoload -U compinit; compinit
+
+Fish:
+  qs completion fish > ~/.config/fish/completions/qs.fish
+
+PowerShell:
+  qs completion powershell | Out-String | Invoke-Expression
+  # add that line to $PROFILE to load it in every session`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	switch args[0] {
+	case "bash":
+		return cmd.Root().GenBashCompletionV2(out, true)
+	case "zsh":
+		return cmd.Root().GenZshCompletion(out)
+	case "fish":
+		return cmd.Root().GenFishCompletion(out, true)
+	default:
+		return cmd.Root().GenPowerShellCompletionWithDesc(out)
+	}
+}
+
+// completionConfig loads the config for a completion function. Completion
+// must stay quiet, so any error just means nothing to offer.
+func completionConfig() *config.Config {
+	cfg, err := config.Load("")
+	if err != nil {
+		return nil
+	}
+	config.EnsureDefaults(cfg)
+	return cfg
+}
+
+// completeProjects completes project references: names in the primary
+// root, root:name for the others, favorites' short names, and folders
+// inside a project once a '/' is typed.
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if strings.Contains(toComplete, "/") {
+		return projectCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+	return projectCompletions(cfg, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeOpenArgs completes qs open's project; anything after it goes to
+// the tool, so is left to the shell.
+func completeOpenArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return completeProjects(cmd, args, toComplete)
+}
+
+// completeAccounts completes enabled account IDs, with their labels as
+// descriptions.
+func completeAccounts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, a := range config.EnabledAccounts(cfg.Accounts) {
+		if strings.HasPrefix(a.ID, toComplete) {
+			ids = append(ids, a.ID+"\t"+a.Label)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEnvArgs completes qs env's account, then its project.
+func completeEnvArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeAccounts(cmd, args, toComplete)
+	case 1:
+		return completeProjects(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeKeysAccount completes the account a qs keys subcommand takes
+// first.
+func completeKeysAccount(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeAccounts(cmd, args, toComplete)
+}
+
+// completeKeysImport completes qs keys import's account, then its file.
+func completeKeysImport(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return completeAccounts(cmd, args, toComplete)
+}
+
+// completeKeysName completes an account, then the names of its keys. An
+// encrypted keys file isn't opened, since that could prompt.
+func completeKeysName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeAccounts(cmd, args, toComplete)
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if encrypted, err := config.KeysEncrypted(); err != nil || encrypted {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for name := range config.UserAPIKeys(keys, args[0]) {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRoots completes root names, with their paths as descriptions.
+func completeRoots(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, r := range cfg.ProjectRoots() {
+		if strings.HasPrefix(r.Name, toComplete) {
+			names = append(names, r.Name+"\t"+r.Path)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProfile completes a profile name as the first argument.
+func completeProfile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProfileFlag(cmd, args, toComplete)
+}
+
+// completeProfileFlag completes profile names.
+func completeProfileFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg := completionConfig()
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, name := range cfg.ProfileNames() {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectCompletions returns the project references starting with
+// toComplete. Up to the last '/' it names a project; after it, the
+// project's subfolders are offered.
+func projectCompletions(cfg *config.Config, toComplete string) []string {
+	var candidates []string
+	if i := strings.LastIndex(toComplete, "/"); i >= 0 {
+		parent := toComplete[:i]
+		dir, err := cfg.ResolveProject(parent)
+		if err != nil {
+			dir = cfg.RefDir(parent)
+		}
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				candidates = append(candidates, parent+"/"+e.Name())
+			}
+		}
+	} else {
+		roots := cfg.ProjectRoots()
+		for _, p := range tui.ListProjects(cfg) {
+			candidates = append(candidates, cfg.ProjectRef(p.Dir))
+			// root:name also works for the primary root
+			if len(roots) > 1 && p.Root == roots[0].Name {
+				candidates = append(candidates, p.Root+":"+filepath.ToSlash(p.Name))
+			}
+		}
+		for _, f := range cfg.Favorites {
+			candidates = append(candidates, path.Base(config.FavoriteKey(f)))
+		}
+	}
+
+	seen := make(map[string]bool)
+	var matches []string
+	for _, c := range candidates {
+		if !seen[c] && strings.HasPrefix(c, toComplete) {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+)
+
+func TestProjectCompletions(t *testing.T) {
+	work := t.TempDir()
+	src := t.TempDir()
+	for _, d := range []string{
+		filepath.Join(work, "api", "cmd"),
+		filepath.Join(work, "api", "internal"),
+		filepath.Join(work, "api", ".git"),
+		filepath.Join(work, "app"),
+		filepath.Join(src, "forks", "cli"),
+	} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		Roots:     []config.ProjectRoot{{Name: "work", Path: work}, {Name: "src", Path: src}},
+		Favorites: []string{"src:forks/cli"},
+	}
+	cfg.NormalizeRoots()
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"a", []string{"api", "app"}},
+		{"src:", []string{"src:forks"}},
+		{"work:a", []string{"work:api", "work:app"}},
+		{"c", []string{"cli"}},
+		{"api/", []string{"api/cmd", "api/internal"}},
+		{"api/i", []string{"api/internal"}},
+		{"src:forks/", []string{"src:forks/cli"}},
+		{"forks/", []string{"forks/cli"}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		got := projectCompletions(cfg, tt.toComplete)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%q: got %v, want %v", tt.toComplete, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var out strings.Builder
+		completionCmd.SetOut(&out)
+		if err := runCompletion(completionCmd, []string{shell}); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "qs") {
+			t.Errorf("%s: expected a script for qs", shell)
+		}
+	}
+	completionCmd.SetOut(nil)
+}
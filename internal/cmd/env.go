@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// envSourceInherited marks variables a tool inherits from qs's own
+// environment.
+const envSourceInherited = "inherited"
+
+var envShowFlag bool
+
+var envOutput outputOptions
+
+var envCmd = &cobra.Command{
+	Use:   "env <account> [project]",
+	Short: "Print the environment a tool is launched with",
+	Long: `Print the environment an account's tool is launched with in a project:
+what its env policy inherits, its API keys and the project's env. Without a
+project, the current directory is used.
+
+Values are masked, since a secret can hide in any of them, e.g. a password
+in DATABASE_URL; --show prints them in full.
+
+--json, --tsv and --format print them for other tools. TSV columns are
+name, value and source.`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeEnvArgs,
+	SilenceUsage:      true,
+	RunE:              runEnv,
+}
+
+func init() {
+	envCmd.Flags().BoolVar(&envShowFlag, "show", false, "Print values in full instead of masked")
+	addOutputFlags(envCmd, &envOutput)
+}
+
+// envInfo is one variable as qs env reports it.
+type envInfo struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`  // masked unless --show
+	Source string `json:"source"` // inherited, account, config or an env file
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	account, err := lookupAccount(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	var dir string
+	if len(args) == 2 {
+		dir, err = resolveOpenProject(cfg, args[1], "")
+	} else {
+		dir, err = os.Getwd()
+	}
+	if err != nil {
+		return err
+	}
+
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	vars, err := config.LaunchEnv(cfg, keys, account.ID, dir)
+	if err != nil {
+		return err
+	}
+	resolved, err := config.ResolveEnv(cfg, vars)
+	if err != nil {
+		return err
+	}
+
+	environ := os.Environ()
+	infos := effectiveEnv(config.BuildEnv(environ, account.Env, resolved), vars, envShowFlag)
+	if envOutput.structured() {
+		return writeList(cmd.OutOrStdout(), envOutput, infos, func(e envInfo) []string {
+			return []string{e.Name, e.Value, e.Source}
+		})
+	}
+
+	fmt.Println()
+	fmt.Printf(" %s %s %s\n",
+		tui.TitleStyle.Render("◆"),
+		tui.WhiteStyle.Render(account.ID),
+		tui.DimStyle.Render("in "+cfg.ProjectRef(dir)))
+	fmt.Println()
+	for _, e := range infos {
+		source := ""
+		if e.Source != envSourceInherited {
+			source = "  " + tui.DimStyle.Render(e.Source)
+		}
+		fmt.Printf("  %s=%s%s\n", e.Name, e.Value, source)
+	}
+	if dropped := config.DroppedEnv(environ, account.Env); len(dropped) > 0 {
+		fmt.Println()
+		fmt.Printf("  %s\n", tui.DimStyle.Render(fmt.Sprintf("%d inherited variables dropped by the env policy", len(dropped))))
+	}
+	fmt.Println()
+	return nil
+}
+
+// effectiveEnv describes a launch environment built from vars, sorted by
+// name, with every value masked unless show is set.
+func effectiveEnv(env []string, vars []config.EnvVar, show bool) []envInfo {
+	sources := make(map[string]string, len(vars))
+	for _, v := range vars {
+		sources[v.Name] = v.Source
+	}
+
+	infos := make([]envInfo, 0, len(env))
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		if name == "" {
+			continue
+		}
+		source, set := sources[name]
+		if !set {
+			source = envSourceInherited
+		}
+		if !show {
+			value = config.MaskValue(value)
+		}
+		infos = append(infos, envInfo{Name: name, Value: value, Source: source})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
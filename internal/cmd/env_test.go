@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+)
+
+func TestEffectiveEnvMasksSecrets(t *testing.T) {
+	env := []string{
+		"PATH=/usr/bin",
+		"AWS_SECRET_ACCESS_KEY=inherited-secret",
+		"GITHUB_TOKEN=ghp_inherited",
+		"OPENAI_API_KEY=sk-from-keys",
+		"DATABASE_URL=postgres://user:pw@localhost/db",
+		"REDIS_URL=redis://:pw@localhost",
+	}
+	vars := []config.EnvVar{
+		{Name: "OPENAI_API_KEY", Value: "env:X", Source: config.EnvSourceAccount},
+		{Name: "DATABASE_URL", Value: "postgres://user:pw@localhost/db", Source: ".qs.env"},
+	}
+
+	want := map[string]envInfo{
+		"PATH":                  {Name: "PATH", Value: config.MaskValue("/usr/bin"), Source: "inherited"},
+		"AWS_SECRET_ACCESS_KEY": {Name: "AWS_SECRET_ACCESS_KEY", Value: config.MaskValue("inherited-secret"), Source: "inherited"},
+		"GITHUB_TOKEN":          {Name: "GITHUB_TOKEN", Value: config.MaskValue("ghp_inherited"), Source: "inherited"},
+		"OPENAI_API_KEY":        {Name: "OPENAI_API_KEY", Value: config.MaskValue("sk-from-keys"), Source: "account"},
+		"DATABASE_URL":          {Name: "DATABASE_URL", Value: config.MaskValue("postgres://user:pw@localhost/db"), Source: ".qs.env"},
+		"REDIS_URL":             {Name: "REDIS_URL", Value: config.MaskValue("redis://:pw@localhost"), Source: "inherited"},
+	}
+	infos := effectiveEnv(env, vars, false)
+	if len(infos) != len(want) {
+		t.Fatalf("got %d variables, want %d: %+v", len(infos), len(want), infos)
+	}
+	for i, e := range infos {
+		if i > 0 && infos[i-1].Name > e.Name {
+			t.Errorf("not sorted: %s before %s", infos[i-1].Name, e.Name)
+		}
+		if e != want[e.Name] {
+			t.Errorf("%s = %+v, want %+v", e.Name, e, want[e.Name])
+		}
+	}
+}
+
+func TestEffectiveEnvShow(t *testing.T) {
+	env := []string{"REDIS_URL=redis://:pw@localhost", "OPENAI_API_KEY=sk-from-keys"}
+	vars := []config.EnvVar{{Name: "OPENAI_API_KEY", Value: "env:X", Source: config.EnvSourceAccount}}
+
+	want := map[string]string{"REDIS_URL": "redis://:pw@localhost", "OPENAI_API_KEY": "sk-from-keys"}
+	for _, e := range effectiveEnv(env, vars, true) {
+		if e.Value != want[e.Name] {
+			t.Errorf("%s = %q, want %q", e.Name, e.Value, want[e.Name])
+		}
+	}
+}
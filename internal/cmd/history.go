@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recently launched projects, most frecent first",
+	Args:  cobra.NoArgs,
+	RunE:  runHistoryList,
+}
+
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget all recorded launches",
+	Args:  cobra.NoArgs,
+	RunE:  runHistoryClear,
+}
+
+func init() {
+	historyCmd.AddCommand(historyClearCmd)
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	unlock, err := config.LockHistory()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	h, err := config.LoadHistory("")
+	if err != nil {
+		return err
+	}
+	if h.Prune() > 0 {
+		if err := config.SaveHistory(h, ""); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	recent := h.Recent(now)
+
+	fmt.Println()
+	if len(recent) == 0 {
+		fmt.Println("  No launches recorded yet.")
+		fmt.Println()
+		return nil
+	}
+
+	for _, rp := range recent {
+		launches := "1 launch"
+		if rp.Launches != 1 {
+			launches = fmt.Sprintf("%d launches", rp.Launches)
+		}
+		fmt.Printf("  %s %s  %s\n",
+			tui.TitleStyle.Render("◆"),
+			tui.WhiteStyle.Render(filepath.Base(rp.Path)),
+			tui.DimStyle.Render(fmt.Sprintf("%s · %s · %s · score %.0f",
+				rp.Account, tui.FormatAge(now.Sub(rp.LastUsed)), launches, rp.Score)))
+		fmt.Printf("    %s\n", tui.DimStyle.Render(rp.Path))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runHistoryClear(cmd *cobra.Command, args []string) error {
+	unlock, err := config.LockHistory()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	h, err := config.LoadHistory("")
+	if err != nil {
+		// Clearing is the fix for an unreadable history
+		h = &config.History{}
+	}
+	n := len(h.Entries)
+	h.Clear()
+	if err := config.SaveHistory(h, ""); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Cleared %d launches from history\n", tui.SuccessStyle.Render("✓"), n)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// shellInit holds the qcd function for each shell. Each runs qs pick,
+// which draws the picker on stderr and prints the chosen directory, and
+// only changes directory when a project was picked.
+var shellInit = map[string]string{
+	"bash": posixInit,
+	"zsh":  posixInit,
+	"fish": `# qs shell integration: qcd [query] jumps to a project picked with qs
+function qcd --description 'cd into a project picked with qs'
+    set -l dir (command qs pick $argv); or return
+    test -n "$dir"; and cd -- $dir
+end
+`,
+	"powershell": `# qs shell integration: qcd [query] jumps to a project picked with qs
+function qcd {
+    $dir = & qs pick @args
+    if ($LASTEXITCODE -eq 0 -and $dir) { Set-Location -LiteralPath $dir }
+}
+`,
+}
+
+const posixInit = `# qs shell integration: qcd [query] jumps to a project picked with qs
+qcd() {
+  local dir
+  dir="$(command qs pick "$@")" || return
+  [ -n "$dir" ] && cd -- "$dir"
+}
+`
+
+var initCmd = &cobra.Command{
+	Use:   "init <bash|zsh|fish|powershell>",
+	Short: "Print shell integration (the qcd function)",
+	Long: `Print shell integration for your shell: a qcd function that opens the
+picker and changes to the chosen project's directory without launching a
+tool. qcd api jumps straight to api when it names a single project.
+
+Bash / Zsh (in ~/.bashrc or ~/.zshrc):
+  eval "$(qs init bash)"    # or: eval "$(qs init zsh)"
+
+Fish (in ~/.config/fish/config.fish):
+  qs init fish | source
+
+PowerShell (in $PROFILE):
+  Invoke-Expression (& qs init powershell | Out-String)`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := fmt.Fprint(cmd.OutOrStdout(), shellInit[args[0]])
+		return err
+	},
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellInit(t *testing.T) {
+	for _, shell := range initCmd.ValidArgs {
+		script, ok := shellInit[shell]
+		if !ok {
+			t.Errorf("%s: no script", shell)
+			continue
+		}
+		if !strings.Contains(script, "qcd") || !strings.Contains(script, "qs pick") {
+			t.Errorf("%s: expected a qcd function running qs pick:\n%s", shell, script)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+var keysEnvKeyFlag bool
+var keysReplaceFlag bool
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage account API keys without the TUI",
+	Long: `Manage the env vars set for each account from ~/.qs/keys.yaml.
+
+Values can be secrets or references resolved at launch, such as
+env:OTHER_VAR, file:~/.secrets/openai or cmd:pass show openai/api.`,
+}
+
+var keysListCmd = &cobra.Command{
+	Use:               "list [account]",
+	Short:             "List keys with masked values",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeKeysAccount,
+	RunE:              runKeysList,
+}
+
+var keysSetCmd = &cobra.Command{
+	Use:   "set <account> <NAME>",
+	Short: "Set a key, reading its value from stdin or a hidden prompt",
+	Long: `Set a key for an account. The value is read from stdin when it's piped,
+otherwise from a prompt that doesn't echo it, so it never lands in shell
+history:
+
+  pass show openai/api | qs keys set codex OPENAI_API_KEY
+  qs keys set codex OPENAI_API_KEY`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeKeysAccount,
+	RunE:              runKeysSet,
+}
+
+var keysUnsetCmd = &cobra.Command{
+	Use:               "unset <account> <NAME>",
+	Short:             "Remove a key",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeKeysName,
+	RunE:              runKeysUnset,
+}
+
+var keysImportCmd = &cobra.Command{
+	Use:   "import <account> <file>",
+	Short: "Set an account's keys from a dotenv file",
+	Long: `Set an account's keys from a dotenv file of NAME=value lines ("-" reads
+stdin). Keys not in the file are kept unless --replace is given.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeKeysImport,
+	RunE:              runKeysImport,
+}
+
+var keysExportCmd = &cobra.Command{
+	Use:   "export <account>",
+	Short: "Print an account's keys as a dotenv file",
+	Long: `Print an account's keys as a dotenv file that qs keys import reads back.
+Values are printed in full; references are printed as written.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeKeysAccount,
+	RunE:              runKeysExport,
+}
+
+var keysEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the keys file with a passphrase",
+	Long: `Encrypt ~/.qs/keys.yaml with AES-256-GCM, under a key derived from a
+passphrase with scrypt. qs asks for the passphrase the first time it needs
+the keys and remembers it for the rest of the login session, up to 12
+hours, so terminals opened by qs all don't ask again; qs keys lock forgets
+it sooner. The unlocked key is kept in $XDG_RUNTIME_DIR, which is cleared at
+logout, or else in a private directory under the temp dir; qs doesn't cache
+it in a directory other users could reach. QS_KEYS_PASSPHRASE supplies it
+without asking.
+
+With --env-key, the file is encrypted with the 32-byte key in QS_KEYS_KEY
+(base64, e.g. from openssl rand -base64 32) and never asks.
+
+Running it on an encrypted file re-encrypts it, e.g. to change the
+passphrase.`,
+	Args: cobra.NoArgs,
+	RunE: runKeysEncrypt,
+}
+
+var keysDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the keys file as plaintext again",
+	Args:  cobra.NoArgs,
+	RunE:  runKeysDecrypt,
+}
+
+var keysLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Forget the remembered passphrase",
+	Args:  cobra.NoArgs,
+	RunE:  runKeysLock,
+}
+
+func init() {
+	keysEncryptCmd.Flags().BoolVar(&keysEnvKeyFlag, "env-key", false, "Encrypt with the key in QS_KEYS_KEY instead of a passphrase")
+	keysImportCmd.Flags().BoolVar(&keysReplaceFlag, "replace", false, "Remove the account's keys that aren't in the file")
+	keysCmd.AddCommand(keysListCmd)
+	keysCmd.AddCommand(keysSetCmd)
+	keysCmd.AddCommand(keysUnsetCmd)
+	keysCmd.AddCommand(keysImportCmd)
+	keysCmd.AddCommand(keysExportCmd)
+	keysCmd.AddCommand(keysEncryptCmd)
+	keysCmd.AddCommand(keysDecryptCmd)
+	keysCmd.AddCommand(keysLockCmd)
+}
+
+func runKeysList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+
+	accounts := cfg.Accounts
+	if len(args) == 1 {
+		account, err := lookupAccount(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		accounts = []config.Account{*account}
+	}
+
+	fmt.Println()
+	listed := 0
+	for _, a := range accounts {
+		ak := config.UserAPIKeys(keys, a.ID)
+		if len(ak) == 0 {
+			continue
+		}
+		listed++
+		fmt.Printf("  %s %s\n", tui.TitleStyle.Render("◆"), tui.WhiteStyle.Render(a.ID))
+		names := make([]string, 0, len(ak))
+		for name := range ak {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("    %s  %s\n", name, tui.DimStyle.Render(config.DisplayKeyValue(cfg, ak[name])))
+		}
+	}
+	if listed == 0 {
+		fmt.Println("  No keys configured.")
+	}
+	fmt.Println()
+	return nil
+}
+
+func runKeysSet(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	account, err := lookupAccount(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	name := args[1]
+	if err := config.ValidateEnvVarName(name); err != nil {
+		return err
+	}
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+
+	value, err := readKeyValue(cmd.InOrStdin(), name)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("no value given for %s", name)
+	}
+	config.SetAccountKey(keys, account.ID, name, value)
+	if err := config.SaveKeys(keys); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Set %s for %s\n", tui.SuccessStyle.Render("✓"), name, account.ID)
+	return nil
+}
+
+func runKeysUnset(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	account, err := lookupAccount(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	name := args[1]
+	if _, ok := config.UserAPIKeys(keys, account.ID)[name]; !ok {
+		return fmt.Errorf("%s has no key %s", account.ID, name)
+	}
+	config.DeleteAccountKey(keys, account.ID, name)
+	if err := config.SaveKeys(keys); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Removed %s from %s\n", tui.SuccessStyle.Render("✓"), name, account.ID)
+	return nil
+}
+
+func runKeysImport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	account, err := lookupAccount(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if args[1] == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[1], err)
+	}
+	vars, err := config.ParseDotenv(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", args[1], err)
+	}
+
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	if keysReplaceFlag {
+		for name := range config.UserAPIKeys(keys, account.ID) {
+			if _, ok := vars[name]; !ok {
+				config.DeleteAccountKey(keys, account.ID, name)
+			}
+		}
+	}
+	for name, value := range vars {
+		config.SetAccountKey(keys, account.ID, name, value)
+	}
+	if err := config.SaveKeys(keys); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Imported %d keys for %s\n", tui.SuccessStyle.Render("✓"), len(vars), account.ID)
+	return nil
+}
+
+func runKeysExport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+	account, err := lookupAccount(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(config.FormatDotenv(config.UserAPIKeys(keys, account.ID)))
+	return err
+}
+
+// lookupAccount finds a configured account by ID.
+func lookupAccount(cfg *config.Config, id string) (*config.Account, error) {
+	account := config.AccountByID(cfg.Accounts, id)
+	if account == nil {
+		return nil, fmt.Errorf("unknown account %q (see qs accounts list)", id)
+	}
+	return account, nil
+}
+
+// readKeyValue reads a key's value: all of in when it's piped, without its
+// trailing newline, or a line typed at a prompt that doesn't echo it.
+func readKeyValue(in io.Reader, name string) (string, error) {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(f.Fd()) {
+		return readSecret("Value for " + name)
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to read value: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func runKeysEncrypt(cmd *cobra.Command, args []string) error {
+	// Unlock an already encrypted file before asking for the new passphrase
+	if _, err := config.LoadKeys(); err != nil {
+		return err
+	}
+
+	passphrase := ""
+	if !keysEnvKeyFlag {
+		var err error
+		if passphrase, err = promptPassphrase("New passphrase"); err != nil {
+			return err
+		}
+		if passphrase == "" {
+			return fmt.Errorf("passphrase cannot be empty")
+		}
+		confirm, err := promptPassphrase("Repeat passphrase")
+		if err != nil {
+			return err
+		}
+		if confirm != passphrase {
+			return fmt.Errorf("passphrases don't match")
+		}
+	}
+	if err := config.EncryptKeys(passphrase); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Encrypted %s\n", tui.SuccessStyle.Render("✓"), config.KeysPath())
+	return nil
+}
+
+func runKeysDecrypt(cmd *cobra.Command, args []string) error {
+	encrypted, err := config.KeysEncrypted()
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		fmt.Printf("  %s\n", tui.DimStyle.Render(config.KeysPath()+" is not encrypted"))
+		return nil
+	}
+	if err := config.DecryptKeys(); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Decrypted %s\n", tui.SuccessStyle.Render("✓"), config.KeysPath())
+	return nil
+}
+
+func runKeysLock(cmd *cobra.Command, args []string) error {
+	if err := config.LockKeys(); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Keys locked\n", tui.SuccessStyle.Render("✓"))
+	return nil
+}
+
+// promptPassphrase reads a passphrase from the terminal without echoing it.
+// It's config.PassphrasePrompt for every qs command.
+func promptPassphrase(prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", errors.New("keys file is encrypted and stdin is not a terminal: set " + config.KeysPassphraseEnv)
+	}
+	return readSecret(prompt)
+}
+
+// readSecret reads a line from the terminal without echoing it, prompting
+// on stderr so stdout stays clean.
+func readSecret(prompt string) (string, error) {
+	fmt.Fprintf(os.Stderr, "  %s: ", prompt)
+	secret, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", strings.ToLower(prompt), err)
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestKeysSetImportExport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	cfg := config.NewDefaultConfig(filepath.Join(home, "dev"))
+	if err := config.Save(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(f func(*cobra.Command, []string) error, stdin string, args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		c := &cobra.Command{}
+		c.SetIn(strings.NewReader(stdin))
+		c.SetOut(&out)
+		if err := f(c, args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	run(runKeysSet, "sk-from-stdin\n", "codex", "OPENAI_API_KEY")
+	keys, err := config.LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := keys["codex"]["OPENAI_API_KEY"]; got != "sk-from-stdin" {
+		t.Errorf("set stored %q", got)
+	}
+
+	env := filepath.Join(home, "codex.env")
+	if err := os.WriteFile(env, []byte("# codex\nOPENAI_BASE_URL=https://api.example.com/v1\nOPENAI_ORG=\"org 1\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keysReplaceFlag = true
+	t.Cleanup(func() { keysReplaceFlag = false })
+	run(runKeysImport, "", "codex", env)
+
+	exported := run(runKeysExport, "", "codex")
+	vars, err := config.ParseDotenv([]byte(exported))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vars) != 2 || vars["OPENAI_BASE_URL"] != "https://api.example.com/v1" || vars["OPENAI_ORG"] != "org 1" {
+		t.Errorf("export after import --replace = %q", exported)
+	}
+
+	run(runKeysUnset, "", "codex", "OPENAI_ORG")
+	keys, _ = config.LoadKeys()
+	if _, ok := keys["codex"]["OPENAI_ORG"]; ok {
+		t.Error("unset left the key")
+	}
+
+	c := &cobra.Command{}
+	c.SetIn(strings.NewReader("x"))
+	if err := runKeysSet(c, []string{"nope", "KEY"}); err == nil {
+		t.Error("set accepted an unknown account")
+	}
+	if err := runKeysSet(c, []string{"codex", "BAD NAME"}); err == nil {
+		t.Error("set accepted an invalid name")
+	}
+	if err := runKeysUnset(c, []string{"codex", "MISSING"}); err == nil {
+		t.Error("unset accepted a missing key")
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/bcmister/qs/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// lsGitWorkers caps how many git processes qs ls --git runs at once.
+const lsGitWorkers = 8
+
+var lsGitFlag bool
+var lsOutput outputOptions
+
+var lsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List projects across all roots",
+	Long: `List projects across all roots, as the picker shows them.
+
+--json, --tsv and --format print them for other tools. TSV columns are
+ref, path and root, followed by branch, state, ahead and behind with --git.
+Templates get the fields of each JSON object: {{.Ref}}, {{.Path}},
+{{.Root}}, {{.Name}} and, with --git, {{.Git.Branch}} and so on.`,
+	Args: cobra.NoArgs,
+	RunE: runLs,
+}
+
+func init() {
+	lsCmd.Flags().BoolVar(&lsGitFlag, "git", false, "Include each repository's branch, state and upstream counts")
+	addOutputFlags(lsCmd, &lsOutput)
+}
+
+// projectInfo is one project as qs ls reports it.
+type projectInfo struct {
+	Name string   `json:"name"` // relative to its root
+	Ref  string   `json:"ref"`  // what --project and qs open accept
+	Path string   `json:"path"`
+	Root string   `json:"root"`
+	Git  *gitInfo `json:"git,omitempty"`
+}
+
+// gitInfo is a repository's status. It's only set with --git, and only for
+// git repositories.
+type gitInfo struct {
+	Branch     string     `json:"branch"`
+	Dirty      bool       `json:"dirty"`
+	Upstream   bool       `json:"upstream"`
+	Ahead      int        `json:"ahead"`
+	Behind     int        `json:"behind"`
+	LastCommit *time.Time `json:"lastCommit,omitempty"`
+}
+
+func runLs(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+
+	projects := listProjectInfo(cfg, tui.ListProjects(cfg), lsGitFlag)
+	if lsOutput.structured() {
+		return writeList(cmd.OutOrStdout(), lsOutput, projects, projectTSV(lsGitFlag))
+	}
+
+	fmt.Println()
+	if len(projects) == 0 {
+		fmt.Println("  No projects found.")
+		fmt.Println()
+		return nil
+	}
+	for _, p := range projects {
+		meta := p.Root
+		if p.Git != nil {
+			meta += " · " + p.Git.Branch
+			if p.Git.Dirty {
+				meta += " *"
+			}
+		}
+		fmt.Printf("  %s %s  %s\n",
+			tui.TitleStyle.Render("◆"),
+			tui.WhiteStyle.Render(p.Ref),
+			tui.DimStyle.Render(meta))
+		fmt.Printf("    %s\n", tui.DimStyle.Render(p.Path))
+	}
+	fmt.Println()
+	return nil
+}
+
+// listProjectInfo describes projects, reading git status in parallel when
+// withGit is set.
+func listProjectInfo(cfg *config.Config, projects []tui.Project, withGit bool) []projectInfo {
+	infos := make([]projectInfo, len(projects))
+	for i, p := range projects {
+		infos[i] = projectInfo{Name: p.Name, Ref: cfg.ProjectRef(p.Dir), Path: p.Dir, Root: p.Root}
+	}
+	if !withGit {
+		return infos
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, lsGitWorkers)
+	for i := range infos {
+		wg.Add(1)
+		go func(info *projectInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			st, err := worktree.StatusOf(info.Path)
+			if err != nil {
+				return
+			}
+			info.Git = &gitInfo{Branch: st.Branch, Dirty: st.Dirty, Upstream: st.Upstream, Ahead: st.Ahead, Behind: st.Behind}
+			if !st.LastCommit.IsZero() {
+				info.Git.LastCommit = &st.LastCommit
+			}
+		}(&infos[i])
+	}
+	wg.Wait()
+	return infos
+}
+
+// projectTSV returns the TSV columns of a project. Git columns are always
+// present with withGit, empty for folders that aren't repositories.
+func projectTSV(withGit bool) func(projectInfo) []string {
+	return func(p projectInfo) []string {
+		row := []string{p.Ref, p.Path, p.Root}
+		if !withGit {
+			return row
+		}
+		if p.Git == nil {
+			return append(row, "", "", "", "")
+		}
+		state := "clean"
+		if p.Git.Dirty {
+			state = "dirty"
+		}
+		return append(row, p.Git.Branch, state, strconv.Itoa(p.Git.Ahead), strconv.Itoa(p.Git.Behind))
+	}
+}
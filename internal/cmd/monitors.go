@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/bcmister/qs/internal/launcher"
 	"github.com/bcmister/qs/internal/monitor"
@@ -13,7 +14,29 @@ import (
 var monitorsCmd = &cobra.Command{
 	Use:   "monitors",
 	Short: "List detected monitors",
-	RunE:  runMonitors,
+	Long: `List detected monitors.
+
+--json, --tsv and --format print them for other tools. TSV columns are
+index, name, x, y, width, height and primary.`,
+	RunE: runMonitors,
+}
+
+var monitorsOutput outputOptions
+
+func init() {
+	addOutputFlags(monitorsCmd, &monitorsOutput)
+}
+
+// monitorInfo is one monitor as qs monitors reports it. Index is 1-based,
+// matching the numbering in profiles and the styled listing.
+type monitorInfo struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Primary bool   `json:"primary"`
 }
 
 func runMonitors(cmd *cobra.Command, args []string) error {
@@ -22,6 +45,16 @@ func runMonitors(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to detect monitors: %w", err)
 	}
 
+	if monitorsOutput.structured() {
+		infos := make([]monitorInfo, len(monitors))
+		for i, m := range monitors {
+			infos[i] = monitorInfo{Index: i + 1, Name: m.Name, X: m.X, Y: m.Y, Width: m.Width, Height: m.Height, Primary: m.Primary}
+		}
+		return writeList(cmd.OutOrStdout(), monitorsOutput, infos, func(m monitorInfo) []string {
+			return []string{strconv.Itoa(m.Index), m.Name, strconv.Itoa(m.X), strconv.Itoa(m.Y), strconv.Itoa(m.Width), strconv.Itoa(m.Height), strconv.FormatBool(m.Primary)}
+		})
+	}
+
 	fmt.Println()
 	fmt.Printf(" %s Detected %d monitors\n\n",
 		tui.TitleStyle.Render("◆"),
@@ -53,8 +86,9 @@ func runMonitors(cmd *cobra.Command, args []string) error {
 		fmt.Println("   " + box.Render(content))
 	}
 
-	fmt.Printf("  %s %dpx\n\n",
-		tui.DimStyle.Render("Invisible border:"),
-		launcher.GetInvisibleBorderWidth())
+	if bw := launcher.GetInvisibleBorderWidth(); bw > 0 {
+		fmt.Printf("  %s %dpx\n", tui.DimStyle.Render("Invisible border:"), bw)
+	}
+	fmt.Println()
 	return nil
 }
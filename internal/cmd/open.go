@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes for qs open, so scripts can tell why nothing was launched.
+// Once the tool runs, its own exit status is passed through.
+const (
+	exitProjectNotFound  = 2
+	exitProjectAmbiguous = 3
+	exitAccountDisabled  = 4
+	exitToolNotInstalled = 5
+)
+
+// maxAmbiguousMatches caps the candidates listed for an ambiguous project.
+const maxAmbiguousMatches = 10
+
+var openToolFlag string
+var openDirFlag string
+
+var openCmd = &cobra.Command{
+	Use:   "open <project> [-- tool args...]",
+	Short: "Launch a tool in a project without the picker",
+	Long: `Launch a tool in a project without the picker, for editors, scripts and
+shell aliases.
+
+The project is an absolute or ./relative path, anything --project accepts
+(name, root:name or a favorite's short name), or a fuzzy match that picks
+out a single project. Arguments after -- are passed to the tool after the
+account's own.
+
+Exit codes:
+  2  project not found
+  3  project is ambiguous
+  4  account unknown or disabled
+  5  tool not installed
+Otherwise qs exits with the tool's status.`,
+	Args:              openArgs,
+	ValidArgsFunction: completeOpenArgs,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	RunE:              runOpen,
+}
+
+func init() {
+	openCmd.Flags().StringVar(&openToolFlag, "tool", "", "Account ID to launch (default: the default account, then the last one used)")
+	openCmd.Flags().StringVar(&openDirFlag, "dir", "", "Only look for the project in this root (name or path)")
+	openCmd.RegisterFlagCompletionFunc("tool", completeAccounts)
+	openCmd.RegisterFlagCompletionFunc("dir", completeRoots)
+}
+
+// openArgs allows exactly one project before --, and anything after it.
+func openArgs(cmd *cobra.Command, args []string) error {
+	n := len(args)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		n = dash
+	}
+	if n != 1 {
+		return fmt.Errorf("expected one project, got %d (pass tool arguments after --)", n)
+	}
+	return nil
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+
+	dir, err := resolveOpenProject(cfg, args[0], openDirFlag)
+	if err != nil {
+		return err
+	}
+	account, err := openAccount(cfg, openToolFlag)
+	if err != nil {
+		return err
+	}
+	path, err := exec.LookPath(account.Command)
+	if err != nil {
+		return &ExitError{Code: exitToolNotInstalled, Err: fmt.Errorf("%s is not installed: %s not found on PATH", account.Label, account.Command)}
+	}
+
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	vars, err := config.LaunchEnv(cfg, keys, account.ID, dir)
+	if err != nil {
+		return err
+	}
+	ak, err := config.ResolveEnv(cfg, vars)
+	if err != nil {
+		return err
+	}
+	c := exec.Command(path, append(account.ResolvedArgs(), args[1:]...)...)
+	c.Dir = dir
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = config.BuildEnv(os.Environ(), account.Env, ak)
+	if err := config.RecordLaunch(dir, account.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "  %s %v\n", tui.WarningStyle.Render("!"), err)
+	}
+
+	// The tool handles Ctrl+C itself; qs just waits for it to exit
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveOpenProject finds the project directory for ref: an explicit
+// path, then a reference ResolveProject understands, then a unique fuzzy
+// match. With root set, only that root is searched.
+func resolveOpenProject(cfg *config.Config, ref, root string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if isPathRef(ref) {
+		dir, err := filepath.Abs(expandTilde(ref))
+		if err == nil && isDir(dir) {
+			return dir, nil
+		}
+		return "", &ExitError{Code: exitProjectNotFound, Err: fmt.Errorf("%s is not a directory", ref)}
+	}
+
+	projects := tui.ListProjects(cfg)
+	var notFound error
+	if root != "" {
+		r, ok := findRoot(cfg, root)
+		if !ok {
+			return "", fmt.Errorf("unknown root %q", root)
+		}
+		if dir := filepath.Join(r.Path, filepath.FromSlash(ref)); isDir(dir) {
+			return dir, nil
+		}
+		var inRoot []tui.Project
+		for _, p := range projects {
+			if p.Root == r.Name {
+				inRoot = append(inRoot, p)
+			}
+		}
+		projects = inRoot
+		notFound = fmt.Errorf("project %q not found in %s", ref, r.Path)
+	} else {
+		dir, err := cfg.ResolveProject(ref)
+		switch {
+		case err == nil:
+			return dir, nil
+		case errors.Is(err, config.ErrAmbiguousProject):
+			return "", &ExitError{Code: exitProjectAmbiguous, Err: err}
+		}
+		notFound = err
+	}
+
+	matches := tui.MatchProjects(ref, projects)
+	switch len(matches) {
+	case 0:
+		return "", &ExitError{Code: exitProjectNotFound, Err: notFound}
+	case 1:
+		return matches[0].Dir, nil
+	}
+	var refs []string
+	for i, p := range matches {
+		if i == maxAmbiguousMatches {
+			refs = append(refs, fmt.Sprintf("and %d more", len(matches)-i))
+			break
+		}
+		refs = append(refs, cfg.ProjectRef(p.Dir))
+	}
+	return "", &ExitError{Code: exitProjectAmbiguous, Err: fmt.Errorf("project %q matches several projects: %s", ref, strings.Join(refs, ", "))}
+}
+
+// openAccount returns the account to launch: id if given, else the default
+// account, the last one used or the first enabled one.
+func openAccount(cfg *config.Config, id string) (*config.Account, error) {
+	if id == "" {
+		for _, candidate := range []string{cfg.DefaultAccount, cfg.LastAccount} {
+			if a := config.AccountByID(cfg.Accounts, candidate); candidate != "" && a != nil && a.Enabled {
+				return a, nil
+			}
+		}
+		for i := range cfg.Accounts {
+			if cfg.Accounts[i].Enabled {
+				return &cfg.Accounts[i], nil
+			}
+		}
+		return nil, &ExitError{Code: exitAccountDisabled, Err: fmt.Errorf("no enabled tools configured: run qs accounts")}
+	}
+
+	a := config.AccountByID(cfg.Accounts, id)
+	if a == nil {
+		return nil, &ExitError{Code: exitAccountDisabled, Err: fmt.Errorf("unknown account %q", id)}
+	}
+	if !a.Enabled {
+		return nil, &ExitError{Code: exitAccountDisabled, Err: fmt.Errorf("account %q is disabled: enable it with qs accounts", id)}
+	}
+	return a, nil
+}
+
+// findRoot looks a root up by name or path.
+func findRoot(cfg *config.Config, root string) (config.ProjectRoot, bool) {
+	abs, _ := filepath.Abs(expandTilde(root))
+	for _, r := range cfg.ProjectRoots() {
+		if strings.EqualFold(r.Name, root) || filepath.Clean(r.Path) == abs {
+			return r, true
+		}
+	}
+	return config.ProjectRoot{}, false
+}
+
+// isPathRef reports whether ref is written as a path rather than a project
+// name: absolute, or starting with ., .. or ~.
+func isPathRef(ref string) bool {
+	if filepath.IsAbs(ref) || ref == "." || ref == ".." || ref == "~" {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", "~/", `.\`, `..\`, `~\`} {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func expandTilde(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
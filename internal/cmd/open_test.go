@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func exitCode(err error) int {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+func TestResolveOpenProject(t *testing.T) {
+	work := t.TempDir()
+	src := t.TempDir()
+	for _, d := range []string{
+		filepath.Join(work, "api-server"),
+		filepath.Join(work, "api-client"),
+		filepath.Join(work, "website"),
+		filepath.Join(src, "api-server"),
+		filepath.Join(src, "dotfiles"),
+	} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Roots: []config.ProjectRoot{{Name: "work", Path: work}, {Name: "src", Path: src}}}
+	cfg.NormalizeRoots()
+
+	tests := []struct {
+		ref, root string
+		want      string
+		code      int
+	}{
+		{ref: "api-server", want: filepath.Join(work, "api-server")},
+		{ref: "src:api-server", want: filepath.Join(src, "api-server")},
+		{ref: "api-server", root: "src", want: filepath.Join(src, "api-server")},
+		{ref: "webs", want: filepath.Join(work, "website")},
+		{ref: "dotf", want: filepath.Join(src, "dotfiles")},
+		{ref: filepath.Join(src, "dotfiles"), want: filepath.Join(src, "dotfiles")},
+		{ref: "apic", want: filepath.Join(work, "api-client")},
+		{ref: "api", code: exitProjectAmbiguous},
+		{ref: "dotf", root: "work", code: exitProjectNotFound},
+		{ref: "nothing", code: exitProjectNotFound},
+		{ref: filepath.Join(src, "missing"), code: exitProjectNotFound},
+		{ref: "api", root: "nope", code: 1},
+	}
+	for _, tt := range tests {
+		got, err := resolveOpenProject(cfg, tt.ref, tt.root)
+		if code := exitCode(err); code != tt.code {
+			t.Errorf("%s (root %q): exit code %d, want %d (%v)", tt.ref, tt.root, code, tt.code, err)
+			continue
+		}
+		if tt.code == 0 && got != tt.want {
+			t.Errorf("%s (root %q): got %q, want %q", tt.ref, tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestOpenAccount(t *testing.T) {
+	cfg := &config.Config{
+		DefaultAccount: "off",
+		LastAccount:    "b",
+		Accounts: []config.Account{
+			{ID: "a", Command: "a", Enabled: true},
+			{ID: "b", Command: "b", Enabled: true},
+			{ID: "off", Command: "off"},
+		},
+	}
+
+	// A disabled default falls back to the last account used
+	if a, err := openAccount(cfg, ""); err != nil || a.ID != "b" {
+		t.Errorf("expected b, got %v, %v", a, err)
+	}
+	if a, err := openAccount(cfg, "a"); err != nil || a.ID != "a" {
+		t.Errorf("expected a, got %v, %v", a, err)
+	}
+	for _, id := range []string{"off", "unknown"} {
+		if _, err := openAccount(cfg, id); exitCode(err) != exitAccountDisabled {
+			t.Errorf("%s: expected exit code %d, got %v", id, exitAccountDisabled, err)
+		}
+	}
+
+	cfg.Accounts = cfg.Accounts[2:]
+	if _, err := openAccount(cfg, ""); exitCode(err) != exitAccountDisabled {
+		t.Errorf("expected exit code %d with no enabled accounts, got %v", exitAccountDisabled, err)
+	}
+}
+
+func TestOpenArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		ok   bool
+	}{
+		{args: []string{"api"}, ok: true},
+		{args: []string{"api", "--", "--model", "x"}, ok: true},
+		{args: []string{"api", "web"}},
+		{args: []string{"--", "api"}},
+		{args: nil},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := openArgs(cmd, cmd.Flags().Args()); (err == nil) != tt.ok {
+			t.Errorf("%v: got %v, want ok=%v", tt.args, err, tt.ok)
+		}
+	}
+}
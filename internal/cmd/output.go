@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// outputOptions are the --json, --tsv and --format flags of the listing
+// commands. With none set, a command prints its usual styled listing.
+type outputOptions struct {
+	json   bool
+	tsv    bool
+	format string
+}
+
+func addOutputFlags(cmd *cobra.Command, o *outputOptions) {
+	cmd.Flags().BoolVar(&o.json, "json", false, "Print a JSON array")
+	cmd.Flags().BoolVar(&o.tsv, "tsv", false, "Print tab-separated rows, without a header")
+	cmd.Flags().StringVar(&o.format, "format", "", "Print each item with a Go template, e.g. '{{.Path}}'")
+	cmd.MarkFlagsMutuallyExclusive("json", "tsv", "format")
+}
+
+// structured reports whether one of the machine-readable outputs was asked
+// for.
+func (o outputOptions) structured() bool {
+	return o.json || o.tsv || o.format != ""
+}
+
+// templateFuncs are available in --format templates.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// writeList prints items in the format o selects. tsvRow gives the columns
+// of an item for --tsv; tabs and newlines in values are replaced by spaces
+// so every item stays on one line.
+func writeList[T any](w io.Writer, o outputOptions, items []T, tsvRow func(T) []string) error {
+	switch {
+	case o.json:
+		if items == nil {
+			items = []T{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case o.tsv:
+		for _, item := range items {
+			row := tsvRow(item)
+			for i, col := range row {
+				row[i] = strings.Map(func(r rune) rune {
+					if r == '\t' || r == '\n' || r == '\r' {
+						return ' '
+					}
+					return r
+				}, col)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		tmpl, err := template.New("format").Funcs(templateFuncs).Parse(o.format)
+		if err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+		for _, item := range items {
+			if err := tmpl.Execute(w, item); err != nil {
+				return fmt.Errorf("invalid --format template: %w", err)
+			}
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+)
+
+func TestWriteList(t *testing.T) {
+	items := []projectInfo{
+		{Name: "api", Ref: "api", Path: "/w/api", Root: "work"},
+		{Name: "cli", Ref: "src:cli", Path: "/s/cli", Root: "src", Git: &gitInfo{Branch: "main\tx", Ahead: 2}},
+	}
+
+	tests := []struct {
+		opts outputOptions
+		want string
+	}{
+		{outputOptions{tsv: true}, "api\t/w/api\twork\t\t\t\t\nsrc:cli\t/s/cli\tsrc\tmain x\tclean\t2\t0\n"},
+		{outputOptions{format: "{{.Ref}}={{.Path}}"}, "api=/w/api\nsrc:cli=/s/cli\n"},
+		{outputOptions{format: "{{json .Git}}"}, "null\n{\"branch\":\"main\\tx\",\"dirty\":false,\"upstream\":false,\"ahead\":2,\"behind\":0}\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeList(&buf, tt.opts, items, projectTSV(true)); err != nil {
+			t.Fatalf("%+v: %v", tt.opts, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.opts, buf.String(), tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeList(&buf, outputOptions{json: true}, items, projectTSV(true)); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []projectInfo
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 || decoded[1].Git.Ahead != 2 || decoded[0].Git != nil {
+		t.Errorf("unexpected JSON %s (%v)", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := writeList(&buf, outputOptions{json: true}, []projectInfo(nil), projectTSV(false)); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected an empty array, got %q (%v)", buf.String(), err)
+	}
+	if err := writeList(&buf, outputOptions{format: "{{.Nope"}, items, projectTSV(false)); err == nil {
+		t.Error("expected a template error")
+	}
+}
+
+func TestAccountsListHidesSecrets(t *testing.T) {
+	cfg := &config.Config{
+		DefaultAccount: "claude",
+		Accounts: []config.Account{
+			{ID: "claude", Label: "Claude", Command: "claude", Enabled: true, PromptArgs: []string{"{prompt}"}},
+		},
+	}
+	keys := config.AccountKeys{"claude": {"ANTHROPIC_API_KEY": "sk-secret-value", "CLAUDE_CODE_EFFORT_LEVEL": "max"}}
+	accounts := listAccountInfo(cfg, keys)
+	if len(accounts) != 1 || !accounts[0].Default || !accounts[0].Prompt || strings.Join(accounts[0].Keys, ",") != "ANTHROPIC_API_KEY" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+
+	for _, opts := range []outputOptions{{json: true}, {tsv: true}, {format: "{{.}}"}, {format: "{{json .}}"}} {
+		var buf bytes.Buffer
+		if err := writeList(&buf, opts, accounts, func(a accountInfo) []string { return []string{a.ID} }); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "sk-secret-value") {
+			t.Errorf("%+v printed a key value: %s", opts, buf.String())
+		}
+	}
+}
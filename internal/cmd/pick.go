@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var pickCmd = &cobra.Command{
+	Use:   "pick [query]",
+	Short: "Pick a project and print its directory",
+	Long: `Pick a project and print its directory instead of launching a tool, for
+shell functions like the qcd that qs init prints.
+
+The picker draws on stderr, so the path can be captured from stdout. A
+query that names exactly one project (as qs open resolves it) skips the
+picker; otherwise the picker opens with the query as its filter. Exits
+with status 1 if the picker is cancelled.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeOpenArgs,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	RunE:              runPick,
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	config.EnsureDefaults(cfg)
+
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+		if dir, err := resolveOpenProject(cfg, query, ""); err == nil {
+			fmt.Fprintln(cmd.OutOrStdout(), dir)
+			return nil
+		}
+	}
+
+	// stdout is usually captured by the shell; style for the terminal the
+	// picker actually draws on
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(os.Stderr))
+	picker := tui.NewPicker(cfg, nil).WithPathOnly()
+	if query != "" {
+		picker = picker.WithFilter(query)
+	}
+	p := tea.NewProgram(picker, tea.WithAltScreen(), tea.WithOutput(os.Stderr))
+	finalModel, err := p.Run()
+	if err != nil {
+		return err
+	}
+	m, ok := finalModel.(tui.PickerModel)
+	if !ok || m.PickedDir() == "" {
+		return &ExitError{Code: 1}
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), m.PickedDir())
+	return nil
+}
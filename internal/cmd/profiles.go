@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:               "profile <name>",
+	Short:             "Launch a named profile (same as qs all --profile)",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfile,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return launchAll(args[0])
+	},
+}
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manage named monitor/window profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfilesList,
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE:  runProfilesList,
+}
+
+var profilesCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Save the current monitor arrangement as a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfilesCreate,
+}
+
+var profilesDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Delete a profile",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfile,
+	RunE:              runProfilesDelete,
+}
+
+var profilesRenameCmd = &cobra.Command{
+	Use:               "rename <old> <new>",
+	Short:             "Rename a profile",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeProfile,
+	RunE:              runProfilesRename,
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesListCmd)
+	profilesCmd.AddCommand(profilesCreateCmd)
+	profilesCmd.AddCommand(profilesDeleteCmd)
+	profilesCmd.AddCommand(profilesRenameCmd)
+}
+
+// loadExistingConfig loads the config for management commands, pointing
+// at `qs setup` when none exists yet.
+func loadExistingConfig() (*config.Config, error) {
+	cfg, err := config.Load("")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no configuration found — run qs setup first")
+		}
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
+
+func runProfilesList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(cfg.Profiles) == 0 {
+		fmt.Println("  No profiles. Create one with `qs profiles create <name>`.")
+		fmt.Println()
+		return nil
+	}
+
+	for _, p := range cfg.Profiles {
+		fmt.Printf("  %s %s  %s\n",
+			tui.TitleStyle.Render("◆"),
+			tui.WhiteStyle.Render(p.Name),
+			tui.DimStyle.Render(describeMonitors(p.Monitors)))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runProfilesCreate(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddProfile(args[0], cfg.Monitors); err != nil {
+		return err
+	}
+	if err := config.Save(cfg, ""); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Created profile %s (%s)\n",
+		tui.SuccessStyle.Render("✓"), args[0], describeMonitors(cfg.Monitors))
+	return nil
+}
+
+func runProfilesDelete(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.DeleteProfile(args[0]); err != nil {
+		return err
+	}
+	if err := config.Save(cfg, ""); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Deleted profile %s\n", tui.SuccessStyle.Render("✓"), args[0])
+	return nil
+}
+
+func runProfilesRename(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.RenameProfile(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := config.Save(cfg, ""); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Renamed profile %s → %s\n", tui.SuccessStyle.Render("✓"), args[0], args[1])
+	return nil
+}
+
+// describeMonitors summarises an arrangement as e.g. "3 grid · 1 full".
+func describeMonitors(monitors []config.MonitorConfig) string {
+	if len(monitors) == 0 {
+		return "no monitors"
+	}
+	s := ""
+	for i, mc := range monitors {
+		if i > 0 {
+			s += " · "
+		}
+		s += fmt.Sprintf("%d %s", mc.WindowCount(), mc.Layout)
+	}
+	return s
+}
@@ -13,12 +13,14 @@ import (
 )
 
 var projectFlag string
+var promptFlag string
 
 var rootCmd = &cobra.Command{
 	Use:   "qs",
 	Short: "Quickstart terminal launcher",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		cleanupOldBinaries()
+		config.PassphrasePrompt = promptPassphrase
 	},
 	RunE: runRoot,
 }
@@ -27,13 +29,46 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// ExitError makes qs exit with Code. A nil Err exits without printing
+// anything, e.g. when passing through a launched tool's status.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func init() {
-	rootCmd.Flags().StringVar(&projectFlag, "project", "", "Pre-select a project and skip to tool selection")
+	rootCmd.Flags().StringVar(&projectFlag, "project", "", "Pre-select a project (name, root:name or a favorite's short name) and skip to tool selection")
+	rootCmd.Flags().StringVar(&promptFlag, "prompt", "", "Initial prompt passed to the tool when it starts")
+	rootCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	// qs completion replaces cobra's default, documenting each shell's setup
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(accountsCmd)
 	rootCmd.AddCommand(monitorsCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(allCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(profilesCmd)
+	rootCmd.AddCommand(worktreesCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(pickCmd)
+	rootCmd.AddCommand(keysCmd)
+	rootCmd.AddCommand(envCmd)
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -67,19 +102,25 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	cfg.ProjectsRoot = projectsRoot
+	cfg.SetProjectsRoot(projectsRoot)
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
 
 	var picker tui.PickerModel
 	if projectFlag != "" {
-		// Validate project directory exists
-		projectDir := filepath.Join(cfg.ProjectsRoot, projectFlag)
-		info, statErr := os.Stat(projectDir)
-		if statErr != nil || !info.IsDir() {
-			return fmt.Errorf("project %q not found in %s", projectFlag, cfg.ProjectsRoot)
+		// Validate the project exists in a root, as root:name or as a
+		// favorite's short name
+		if _, err := cfg.ResolveProject(projectFlag); err != nil {
+			return err
 		}
-		picker = tui.NewPickerWithProject(cfg, projectFlag)
+		picker = tui.NewPickerWithProject(cfg, keys, projectFlag)
 	} else {
-		picker = tui.NewPicker(cfg)
+		picker = tui.NewPicker(cfg, keys)
+	}
+	if promptFlag != "" {
+		picker = picker.WithPrompt(promptFlag)
 	}
 	p := tea.NewProgram(picker, tea.WithAltScreen())
 	finalModel, err := p.Run()
@@ -111,7 +152,11 @@ func runFirstRunFlow(existing *config.Config) (*config.Config, error) {
 	case tui.FirstRunQuit:
 		return nil, nil
 	case tui.FirstRunLaunchSetup:
-		setup := tui.NewSetup(existing)
+		keys, err := config.LoadKeys()
+		if err != nil {
+			return nil, err
+		}
+		setup := tui.NewSetup(existing, keys)
 		setupProgram := tea.NewProgram(setup, tea.WithAltScreen())
 		if _, err := setupProgram.Run(); err != nil {
 			return nil, err
@@ -140,9 +185,25 @@ func runFirstRunFlow(existing *config.Config) (*config.Config, error) {
 		if cfg == nil {
 			cfg = config.NewDefaultConfig(normalizedRoot)
 		} else {
+			cfg.Roots = nil
 			cfg.ProjectsRoot = normalizedRoot
 			config.EnsureDefaults(cfg)
 		}
+		// The first root entered is the primary one, already set above
+		cfg.NormalizeRoots()
+		for i, root := range result.Roots {
+			if i == 0 {
+				cfg.Roots[0].Name = root.Name
+				continue
+			}
+			path, err := ensureProjectsRoot(root.Path)
+			if err != nil {
+				return nil, err
+			}
+			if err := cfg.AddRoot(root.Name, path); err != nil {
+				return nil, err
+			}
+		}
 
 		if err := config.Save(cfg, ""); err != nil {
 			return nil, fmt.Errorf("failed to save config: %w", err)
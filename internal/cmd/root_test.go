@@ -3,7 +3,10 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/bcmister/qs/internal/config"
 )
 
 func TestCleanupOldBinaries(t *testing.T) {
@@ -37,3 +40,127 @@ func TestCleanupOldBinaries(t *testing.T) {
 		}
 	}
 }
+
+func TestTmuxWindows(t *testing.T) {
+	cfg := &config.Config{
+		ProjectsRoot: "/home/me/dev",
+		Monitors: []config.MonitorConfig{
+			{Layout: "grid", Windows: make([]config.WindowConfig, 3)},
+			{Layout: "full", Windows: make([]config.WindowConfig, 2)},
+			{Layout: "vertical"},
+		},
+	}
+
+	windows := tmuxWindows(cfg, nil)
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 windows (empty monitor skipped), got %d", len(windows))
+	}
+	if windows[0].Name != "monitor-1" || len(windows[0].Panes) != 3 {
+		t.Errorf("window 0: got %s with %d panes", windows[0].Name, len(windows[0].Panes))
+	}
+	if len(windows[1].Panes) != 1 {
+		t.Errorf("full layout should get 1 pane, got %d", len(windows[1].Panes))
+	}
+	for _, w := range windows {
+		for _, p := range w.Panes {
+			if p.WorkingDir != cfg.ProjectsRoot || p.Command != "qs" {
+				t.Errorf("unexpected pane %+v", p)
+			}
+		}
+	}
+}
+
+func TestTmuxAllSessionName(t *testing.T) {
+	tests := map[string]string{
+		"":            "qs-all",
+		"review":      "qs-all-review",
+		"work.laptop": "qs-all-work_laptop",
+		"a:b":         "qs-all-a_b",
+	}
+	for profile, want := range tests {
+		if got := tmuxAllSessionName(profile); got != want {
+			t.Errorf("tmuxAllSessionName(%q) = %q, want %q", profile, got, want)
+		}
+	}
+}
+
+func TestWindowLaunchConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewDefaultConfig(root)
+	keys := config.AccountKeys{"codex": {"OPENAI_API_KEY": "sk-test"}}
+
+	t.Run("unassigned opens picker", func(t *testing.T) {
+		lc := windowLaunchConfig(cfg, keys, 0, 1, config.WindowConfig{Tool: "codex"})
+		if lc.Command != "qs" || lc.WorkingDir != root || lc.Title != "qs-1-2" {
+			t.Errorf("unexpected launch config %+v", lc)
+		}
+	})
+
+	t.Run("assigned launches account in project", func(t *testing.T) {
+		win := config.WindowConfig{Tool: "codex", Project: "api", Args: []string{"--model", "o3"}, Title: "api-review"}
+		lc := windowLaunchConfig(cfg, keys, 0, 0, win)
+		if lc.Command != "codex" || lc.WorkingDir != filepath.Join(root, "api") || lc.Title != "api-review" {
+			t.Errorf("unexpected launch config %+v", lc)
+		}
+		if n := len(lc.Args); n < 2 || lc.Args[n-2] != "--model" || lc.Args[n-1] != "o3" {
+			t.Errorf("window args not appended: %q", lc.Args)
+		}
+		if !slices.Contains(lc.Env, "OPENAI_API_KEY=sk-test") {
+			t.Errorf("account keys not injected: %v", lc.Env)
+		}
+	})
+
+	t.Run("env policy drops inherited vars", func(t *testing.T) {
+		t.Setenv("QS_TEST_DENIED", "secret")
+		t.Setenv("QS_TEST_KEPT", "kept")
+		denied := config.NewDefaultConfig(root)
+		config.AccountByID(denied.Accounts, "codex").Env.Deny = []string{"QS_TEST_DENIED"}
+		lc := windowLaunchConfig(denied, keys, 0, 0, config.WindowConfig{Tool: "codex", Project: "api"})
+		if slices.Contains(lc.Env, "QS_TEST_DENIED=secret") || !slices.Contains(lc.Env, "QS_TEST_KEPT=kept") {
+			t.Errorf("env policy not applied: %v", lc.Env)
+		}
+	})
+
+	t.Run("project in another root", func(t *testing.T) {
+		work := t.TempDir()
+		if err := os.Mkdir(filepath.Join(work, "svc"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		multi := config.NewDefaultConfig(root)
+		if err := multi.AddRoot("work", work); err != nil {
+			t.Fatal(err)
+		}
+		for _, project := range []string{"work:svc", "svc"} {
+			lc := windowLaunchConfig(multi, keys, 0, 0, config.WindowConfig{Tool: "codex", Project: project})
+			if lc.Command != "codex" || lc.WorkingDir != filepath.Join(work, "svc") {
+				t.Errorf("%s: unexpected launch config %+v", project, lc)
+			}
+		}
+	})
+
+	t.Run("missing project falls back to picker", func(t *testing.T) {
+		lc := windowLaunchConfig(cfg, keys, 0, 0, config.WindowConfig{Tool: "codex", Project: "gone"})
+		if lc.Command != "qs" || lc.WorkingDir != root {
+			t.Errorf("expected picker fallback, got %+v", lc)
+		}
+	})
+
+	t.Run("disabled account falls back to picker", func(t *testing.T) {
+		disabled := config.NewDefaultConfig(root)
+		config.AccountByID(disabled.Accounts, "codex").Enabled = false
+		lc := windowLaunchConfig(disabled, keys, 0, 0, config.WindowConfig{Tool: "codex", Project: "api"})
+		if lc.Command != "qs" || lc.WorkingDir != root || len(lc.Env) != 0 {
+			t.Errorf("expected picker fallback, got %+v", lc)
+		}
+	})
+
+	t.Run("unknown account falls back to picker", func(t *testing.T) {
+		lc := windowLaunchConfig(cfg, keys, 0, 0, config.WindowConfig{Tool: "nope", Project: "api"})
+		if lc.Command != "qs" {
+			t.Errorf("expected picker fallback, got %+v", lc)
+		}
+	})
+}
@@ -17,8 +17,13 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	// Load existing config if available (nil is fine for first run)
 	existing, _ := config.Load("")
 
-	setup := tui.NewSetup(existing)
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+
+	setup := tui.NewSetup(existing, keys)
 	p := tea.NewProgram(setup, tea.WithAltScreen())
-	_, err := p.Run()
+	_, err = p.Run()
 	return err
 }
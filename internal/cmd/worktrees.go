@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/tui"
+	"github.com/bcmister/qs/internal/worktree"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var pruneForce bool
+
+var worktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "List, open and prune git worktrees created by qs",
+	Args:  cobra.NoArgs,
+	RunE:  runWorktreesList,
+}
+
+var worktreesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List worktrees with their branch and dirty state",
+	Args:  cobra.NoArgs,
+	RunE:  runWorktreesList,
+}
+
+var worktreesOpenCmd = &cobra.Command{
+	Use:   "open <name>",
+	Short: "Pick an account and launch it in a worktree",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorktreesOpen,
+}
+
+var worktreesPruneCmd = &cobra.Command{
+	Use:   "prune [name...]",
+	Short: "Remove worktrees (all clean ones if no names are given)",
+	Long: `Remove worktrees, or all clean ones if no names are given.
+
+Stale worktrees, whose repository can't be found, are skipped unless they
+are named or --force is given: if the repository was only moved or renamed,
+git worktree repair reconnects them with their uncommitted changes intact.`,
+	RunE: runWorktreesPrune,
+}
+
+func init() {
+	worktreesPruneCmd.Flags().BoolVar(&pruneForce, "force", false, "Also remove worktrees with uncommitted changes and stale ones")
+	worktreesCmd.AddCommand(worktreesListCmd)
+	worktreesCmd.AddCommand(worktreesOpenCmd)
+	worktreesCmd.AddCommand(worktreesPruneCmd)
+}
+
+func runWorktreesList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	root := cfg.WorktreeRoot()
+	worktrees, err := worktree.List(root)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(worktrees) == 0 {
+		fmt.Printf("  No worktrees in %s\n", root)
+		fmt.Println("  Create one with w in the picker's account stage.")
+		fmt.Println()
+		return nil
+	}
+
+	for _, wt := range worktrees {
+		state := tui.SuccessStyle.Render("clean")
+		switch {
+		case wt.Stale():
+			state = tui.ErrorStyle.Render("stale")
+		case wt.Dirty:
+			state = tui.WarningStyle.Render("dirty")
+		}
+		fmt.Printf("  %s %s  %s  %s\n",
+			tui.TitleStyle.Render("◆"),
+			tui.WhiteStyle.Render(wt.Name),
+			tui.DimStyle.Render(wt.Branch),
+			state)
+		fmt.Printf("    %s\n", tui.DimStyle.Render(wt.Path))
+	}
+	fmt.Println()
+	return nil
+}
+
+func runWorktreesOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	wt, err := worktree.Find(cfg.WorktreeRoot(), args[0])
+	if err != nil {
+		return err
+	}
+	if wt.Stale() {
+		return fmt.Errorf("worktree %s is stale (its repository is gone or moved) — run git worktree repair in a moved repository, or qs worktrees prune %s", wt.Name, wt.Name)
+	}
+
+	config.EnsureDefaults(cfg)
+	keys, err := config.LoadKeys()
+	if err != nil {
+		return err
+	}
+	p := tea.NewProgram(tui.NewPickerInDir(cfg, keys, wt.Path), tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if m, ok := finalModel.(tui.PickerModel); ok && m.Err() != nil {
+		return fmt.Errorf("tool exited with error: %w", m.Err())
+	}
+	return nil
+}
+
+func runWorktreesPrune(cmd *cobra.Command, args []string) error {
+	cfg, err := loadExistingConfig()
+	if err != nil {
+		return err
+	}
+	root := cfg.WorktreeRoot()
+
+	var targets []worktree.Worktree
+	if len(args) == 0 {
+		targets, err = worktree.List(root)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, name := range args {
+			wt, err := worktree.Find(root, name)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, wt)
+		}
+	}
+
+	for _, wt := range targets {
+		if wt.Dirty && !pruneForce {
+			fmt.Printf("  %s Skipped %s (uncommitted changes, use --force)\n", tui.WarningStyle.Render("!"), wt.Name)
+			continue
+		}
+		// Naming a stale worktree is as explicit as --force
+		force := pruneForce || wt.Stale() && len(args) > 0
+		if wt.Stale() && !force {
+			fmt.Printf("  %s %s: stale (skipped)\n", tui.WarningStyle.Render("!"), wt.Name)
+			fmt.Printf("    %s\n", tui.DimStyle.Render("If its repository moved, run git worktree repair "+wt.Path+" in it; otherwise prune it by name or with --force"))
+			continue
+		}
+		if err := worktree.Remove(wt, force); err != nil {
+			fmt.Printf("  %s %s: %v\n", tui.ErrorStyle.Render("✗"), wt.Name, err)
+			continue
+		}
+		fmt.Printf("  %s Removed %s\n", tui.SuccessStyle.Render("✓"), wt.Name)
+	}
+	if len(targets) == 0 {
+		fmt.Println("  No worktrees to prune.")
+	}
+	return nil
+}
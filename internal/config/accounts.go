@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -76,6 +77,8 @@ func CloneAccount(src Account, newLabel string, existing []Account) Account {
 		Icon:       src.Icon,
 		Enabled:    true,
 		AuthUser:   "", // new clone needs fresh auth
+		PromptArgs: src.PromptArgs.Clone(),
+		Env:        src.Env.Clone(),
 	}
 }
 
@@ -101,6 +104,7 @@ func ParseAuthStatus(data []byte) (email string, org string, err error) {
 }
 
 // ProbeAuthUser runs the auth status command for the given tool and returns email and org.
+// env is the command's whole environment, as from BuildEnv; nil inherits qs's.
 // Returns empty strings (no error) if the tool has no auth status command.
 func ProbeAuthUser(command string, env []string) (email string, org string, err error) {
 	statusCmd, ok := AuthStatusCmds[command]
@@ -110,9 +114,7 @@ func ProbeAuthUser(command string, env []string) (email string, org string, err
 
 	parts := strings.Fields(statusCmd)
 	c := exec.Command(parts[0], parts[1:]...)
-	if len(env) > 0 {
-		c.Env = append(os.Environ(), env...)
-	}
+	c.Env = env
 	out, err := c.Output()
 	if err != nil {
 		return "", "", err
@@ -122,15 +124,36 @@ func ProbeAuthUser(command string, env []string) (email string, org string, err
 
 // Account represents a configured AI tool account
 type Account struct {
-	ID         string   `yaml:"id"`
-	Label      string   `yaml:"label"`
-	Command    string   `yaml:"command"`
-	Args       []string `yaml:"args"`
-	AuthCmd    string   `yaml:"authCmd,omitempty"`
-	InstallCmd string   `yaml:"installCmd,omitempty"`
-	Icon       string   `yaml:"icon"`
-	Enabled    bool     `yaml:"enabled"`
-	AuthUser   string   `yaml:"authUser,omitempty"`
+	ID         string    `yaml:"id"`
+	Label      string    `yaml:"label"`
+	Command    string    `yaml:"command"`
+	Args       []string  `yaml:"args"`
+	AuthCmd    string    `yaml:"authCmd,omitempty"`
+	InstallCmd string    `yaml:"installCmd,omitempty"`
+	Icon       string    `yaml:"icon"`
+	Enabled    bool      `yaml:"enabled"`
+	AuthUser   string    `yaml:"authUser,omitempty"`
+	PromptArgs ArgList   `yaml:"promptArgs,omitempty"` // how to pass an initial prompt; {prompt} is replaced
+	Env        EnvPolicy `yaml:"env,omitempty"`        // which of qs's environment the tool inherits
+}
+
+// ArgList is a list of arguments that tells an empty list apart from an
+// unset one: promptArgs: [] turns a built-in account's prompt mapping off,
+// while leaving promptArgs out keeps the default.
+type ArgList []string
+
+// IsZero makes omitempty drop only an unset list, so an empty one survives
+// a save.
+func (l ArgList) IsZero() bool {
+	return l == nil
+}
+
+// Clone returns a copy of l that is empty rather than nil if l is.
+func (l ArgList) Clone() ArgList {
+	if l == nil {
+		return nil
+	}
+	return append(ArgList{}, l...)
 }
 
 // AuthCommand splits AuthCmd into command and args.
@@ -172,6 +195,7 @@ var DefaultAccounts = []Account{
 		InstallCmd: "npm i -g @anthropic-ai/claude-code",
 		Icon:       "\U0001F7E0",
 		Enabled:    true,
+		PromptArgs: []string{PromptPlaceholder},
 	},
 	{
 		ID:         "codex",
@@ -182,6 +206,7 @@ var DefaultAccounts = []Account{
 		InstallCmd: "npm i -g @openai/codex",
 		Icon:       "\U0001F7E2",
 		Enabled:    true,
+		PromptArgs: []string{PromptPlaceholder},
 	},
 	{
 		ID:         "gemini",
@@ -192,6 +217,7 @@ var DefaultAccounts = []Account{
 		InstallCmd: "npm i -g @google/gemini-cli",
 		Icon:       "\U0001F535",
 		Enabled:    true,
+		PromptArgs: []string{"-i", PromptPlaceholder},
 	},
 	{
 		ID:         "opencode",
@@ -212,6 +238,7 @@ var DefaultAccounts = []Account{
 		InstallCmd: "npm i -g @anthropic-ai/claude-code",
 		Icon:       "\U0001F7E3",
 		Enabled:    true,
+		PromptArgs: []string{PromptPlaceholder},
 	},
 	{
 		ID:      "cursor",
@@ -271,3 +298,44 @@ func (a *Account) ResolvedArgs() []string {
 	}
 	return append(out, "--effort", "max")
 }
+
+// PromptPlaceholder marks where the prompt goes in Account.PromptArgs.
+const PromptPlaceholder = "{prompt}"
+
+// SupportsPrompt reports whether the account has a prompt mapping.
+func (a *Account) SupportsPrompt() bool {
+	return len(a.PromptArgs) > 0
+}
+
+// PromptedArgs returns ResolvedArgs followed by PromptArgs with the prompt
+// filled in, e.g. gemini's "-i <prompt>". An empty prompt returns
+// ResolvedArgs alone. Accounts without a mapping return an error instead
+// of dropping the prompt.
+//
+// A prompt starting with "-" would be read as an option, so a positional
+// one is preceded by "--", and one passed as a separate option value is
+// refused.
+func (a *Account) PromptedArgs(prompt string) ([]string, error) {
+	args := a.ResolvedArgs()
+	if strings.TrimSpace(prompt) == "" {
+		return args, nil
+	}
+	if !a.SupportsPrompt() {
+		return nil, fmt.Errorf("%s doesn't support an initial prompt: add promptArgs to the %q account in the config", a.Label, a.ID)
+	}
+	optionsEnded := false
+	for i, arg := range a.PromptArgs {
+		if arg == "--" {
+			optionsEnded = true
+		}
+		if arg == PromptPlaceholder && strings.HasPrefix(prompt, "-") && !optionsEnded {
+			if i > 0 && strings.HasPrefix(a.PromptArgs[i-1], "-") {
+				return nil, fmt.Errorf("%s can't take a prompt starting with \"-\"", a.Label)
+			}
+			args = append(args, "--")
+			optionsEnded = true
+		}
+		args = append(args, strings.ReplaceAll(arg, PromptPlaceholder, prompt))
+	}
+	return args, nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -104,6 +105,7 @@ func TestCloneAccount(t *testing.T) {
 		InstallCmd: "npm i -g @anthropic-ai/claude-code",
 		Icon:       "\U0001F7E0",
 		Enabled:    true,
+		Env:        EnvPolicy{Allow: []string{"AWS_*"}, Deny: []string{"AWS_SECRET_ACCESS_KEY"}},
 	}
 	existing := []Account{src}
 
@@ -136,6 +138,11 @@ func TestCloneAccount(t *testing.T) {
 	if src.Args[0] == "modified" {
 		t.Error("clone args should be a deep copy, but modifying clone affected source")
 	}
+	clone.Env.Allow[0] = "modified"
+	clone.Env.Deny[0] = "modified"
+	if src.Env.Allow[0] == "modified" || src.Env.Deny[0] == "modified" {
+		t.Error("clone env policy should be a deep copy, but modifying clone affected source")
+	}
 }
 
 func TestCloneAccount_IDCollision(t *testing.T) {
@@ -267,6 +274,89 @@ func TestResolvedArgs_ReturnsCopyNotReference(t *testing.T) {
 	}
 }
 
+func TestPromptedArgs(t *testing.T) {
+	cases := []struct {
+		name    string
+		account Account
+		prompt  string
+		want    []string
+	}{
+		{"positional", Account{Command: "codex", Args: []string{"--yolo"}, PromptArgs: []string{PromptPlaceholder}}, "fix the tests", []string{"--yolo", "fix the tests"}},
+		{"flag", Account{Command: "gemini", PromptArgs: []string{"-i", PromptPlaceholder}}, "add docs", []string{"-i", "add docs"}},
+		{"claude keeps effort before the prompt", Account{Command: "claude", PromptArgs: []string{PromptPlaceholder}}, "hi", []string{"--effort", "max", "hi"}},
+		{"embedded placeholder", Account{Command: "tool", PromptArgs: []string{"--task={prompt}"}}, "go", []string{"--task=go"}},
+		{"no prompt", Account{Command: "tool"}, "  ", []string{}},
+		{"dash prompt ends options", Account{Command: "codex", PromptArgs: []string{PromptPlaceholder}}, "-v is broken", []string{"--", "-v is broken"}},
+		{"dash prompt after --", Account{Command: "tool", PromptArgs: []string{"--", PromptPlaceholder}}, "-v", []string{"--", "-v"}},
+		{"dash prompt embedded", Account{Command: "tool", PromptArgs: []string{"--task={prompt}"}}, "-v", []string{"--task=-v"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.account.PromptedArgs(tc.prompt)
+			if err != nil {
+				t.Fatalf("PromptedArgs: %v", err)
+			}
+			if !stringSliceEqual(got, tc.want) {
+				t.Errorf("PromptedArgs = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	a := Account{ID: "custom", Label: "Custom", Command: "custom"}
+	if _, err := a.PromptedArgs("do it"); err == nil || !strings.Contains(err.Error(), "doesn't support an initial prompt") {
+		t.Errorf("expected an error for an account without a mapping, got %v", err)
+	}
+	gemini := Account{ID: "gemini", Label: "Gemini CLI", Command: "gemini", PromptArgs: []string{"-i", PromptPlaceholder}}
+	if _, err := gemini.PromptedArgs("-v"); err == nil {
+		t.Error("expected a prompt starting with - to be refused as an option value")
+	}
+}
+
+func TestDefaultAccounts_PromptMappings(t *testing.T) {
+	want := map[string][]string{
+		"claude": {PromptPlaceholder},
+		"codex":  {PromptPlaceholder},
+		"gemini": {"-i", PromptPlaceholder},
+	}
+	for id, args := range want {
+		a := AccountByID(DefaultAccounts, id)
+		if a == nil || !stringSliceEqual(a.PromptArgs, args) {
+			t.Errorf("expected %s PromptArgs %v, got %+v", id, args, a)
+		}
+	}
+
+	cfg := &Config{Accounts: []Account{{ID: "gemini", Command: "gemini", Enabled: true}}}
+	EnsureDefaults(cfg)
+	if !AccountByID(cfg.Accounts, "gemini").SupportsPrompt() {
+		t.Error("expected EnsureDefaults to backfill gemini's prompt mapping")
+	}
+}
+
+func TestPromptArgsClearedStaysCleared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "version: 4\nprojectsRoot: /test\naccounts:\n  - id: claude\n    command: claude\n    enabled: true\n    promptArgs: []\n  - id: codex\n    command: codex\n    enabled: true\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for round := 0; round < 2; round++ {
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		EnsureDefaults(cfg)
+		if a := AccountByID(cfg.Accounts, "claude"); a.SupportsPrompt() {
+			t.Errorf("round %d: cleared prompt mapping was backfilled: %v", round, a.PromptArgs)
+		}
+		if a := AccountByID(cfg.Accounts, "codex"); !a.SupportsPrompt() {
+			t.Errorf("round %d: unset prompt mapping was not backfilled", round)
+		}
+		if err := Save(cfg, path); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+}
+
 func stringSliceEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -9,9 +9,14 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// WindowConfig represents configuration for a single window within a monitor
+// WindowConfig represents configuration for a single window within a monitor.
+// A window with a Project launches Tool directly in that project from `qs all`;
+// without one it opens the picker.
 type WindowConfig struct {
-	Tool string `yaml:"tool"`
+	Tool    string   `yaml:"tool"`
+	Project string   `yaml:"project,omitempty"` // a project reference, as for --project, or absolute
+	Args    []string `yaml:"args,omitempty"`    // appended to the account's args
+	Title   string   `yaml:"title,omitempty"`   // window title, defaults to qs-<monitor>-<window>
 }
 
 // MonitorConfig represents configuration for a single monitor
@@ -33,14 +38,41 @@ func (mc *MonitorConfig) ToolFor(idx int) string {
 	return "claude"
 }
 
+// WindowAt returns the config of the window at index idx, or a zero
+// WindowConfig (picker, default tool) if none is configured.
+func (mc *MonitorConfig) WindowAt(idx int) WindowConfig {
+	if idx >= 0 && idx < len(mc.Windows) {
+		return mc.Windows[idx]
+	}
+	return WindowConfig{}
+}
+
 // Config represents the application configuration (v4)
 type Config struct {
-	Version        int             `yaml:"version"`
-	ProjectsRoot   string          `yaml:"projectsRoot"`
-	DefaultAccount string          `yaml:"defaultAccount,omitempty"`
-	LastAccount    string          `yaml:"lastAccount,omitempty"`
-	Accounts       []Account       `yaml:"accounts"`
-	Monitors       []MonitorConfig `yaml:"monitors"`
+	Version        int               `yaml:"version"`
+	ProjectsRoot   string            `yaml:"projectsRoot"`    // primary root, kept equal to Roots[0].Path
+	Roots          []ProjectRoot     `yaml:"roots,omitempty"` // named project roots merged in the picker
+	DefaultAccount string            `yaml:"defaultAccount,omitempty"`
+	LastAccount    string            `yaml:"lastAccount,omitempty"`
+	Accounts       []Account         `yaml:"accounts"`
+	Monitors       []MonitorConfig   `yaml:"monitors"`
+	Terminal       string            `yaml:"terminal,omitempty"` // launcher backend for `qs all`: auto, wt, tmux, kitty, wezterm
+	Profiles       []Profile         `yaml:"profiles,omitempty"`
+	WorktreeDir    string            `yaml:"worktreeDir,omitempty"` // where picker worktrees are created, default ~/.qs/worktrees
+	Favorites      []string          `yaml:"favorites,omitempty"`   // pinned projects, relative to projectsRoot
+	Discovery      DiscoveryConfig   `yaml:"discovery,omitempty"`
+	Templates      []Template        `yaml:"templates,omitempty"`     // offered when creating a project folder
+	SecretHelpers  map[string]string `yaml:"secretHelpers,omitempty"` // scheme → command resolving SCHEME:// key references, {ref} is replaced
+	Projects       []ProjectSettings `yaml:"projects,omitempty"`      // per-project env for launches
+}
+
+// DiscoveryConfig turns on recursive project discovery: instead of the
+// direct children of each root, the picker lists every directory below the
+// roots that contains one of Markers, down to Depth levels.
+type DiscoveryConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Depth   int      `yaml:"depth,omitempty"`   // default 3
+	Markers []string `yaml:"markers,omitempty"` // default [.git]
 }
 
 // v2Config is the old format used for migration
@@ -80,6 +112,17 @@ func DefaultProjectsRoot() string {
 	return filepath.Join(homeDir, ".1dev")
 }
 
+// WorktreeRoot returns the directory new git worktrees are created under:
+// WorktreeDir with a leading ~ expanded, or ~/.qs/worktrees when unset.
+func (c *Config) WorktreeRoot() string {
+	dir := strings.TrimSpace(c.WorktreeDir)
+	if dir == "" {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, ".qs", "worktrees")
+	}
+	return expandHome(dir)
+}
+
 // IsFirstRun returns true if no config file exists (neither new nor legacy)
 func IsFirstRun() bool {
 	return isFirstRunAt(DefaultConfigPath(), LegacyConfigPath())
@@ -126,18 +169,25 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	var cfg *Config
 	switch {
 	case peek.Version < 3:
-		return migrateV2(data)
+		cfg, err = migrateV2(data)
 	case peek.Version == 3:
-		return migrateV3(data)
+		cfg, err = migrateV3(data)
 	default:
-		var cfg Config
-		if err := yaml.Unmarshal(data, &cfg); err != nil {
-			return nil, fmt.Errorf("failed to parse config: %w", err)
+		cfg = &Config{}
+		if err = yaml.Unmarshal(data, cfg); err != nil {
+			err = fmt.Errorf("failed to parse config: %w", err)
 		}
-		return &cfg, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Single-root configs gain a Roots list; ProjectsRoot stays the primary
+	cfg.NormalizeRoots()
+	return cfg, nil
 }
 
 // migrateV2 converts a v2 config to v4
@@ -236,6 +286,7 @@ func copyDefaultAccounts() []Account {
 			Icon:       a.Icon,
 			Enabled:    a.Enabled,
 			AuthUser:   a.AuthUser,
+			PromptArgs: a.PromptArgs.Clone(),
 		}
 	}
 	return accounts
@@ -258,6 +309,7 @@ func mergeNewDefaults(cfg *Config) {
 				InstallCmd: d.InstallCmd,
 				Icon:       d.Icon,
 				Enabled:    d.Enabled,
+				PromptArgs: d.PromptArgs.Clone(),
 			})
 		}
 	}
@@ -322,7 +374,7 @@ func EnsureDefaults(cfg *Config) {
 	ensureAccountDefaults(cfg)
 }
 
-// ensureAccountDefaults syncs args, auth, and install commands for built-in account IDs.
+// ensureAccountDefaults syncs args, auth, install and prompt mappings for built-in account IDs.
 // Built-in accounts (those matching a DefaultAccounts ID) always get current defaults
 // for Args, AuthCmd, and InstallCmd. Users who want custom args should clone the account.
 func ensureAccountDefaults(cfg *Config) {
@@ -343,6 +395,10 @@ func ensureAccountDefaults(cfg *Config) {
 		if cfg.Accounts[i].InstallCmd == "" {
 			cfg.Accounts[i].InstallCmd = da.InstallCmd
 		}
+		// Only when unset: promptArgs: [] turns the mapping off
+		if cfg.Accounts[i].PromptArgs == nil {
+			cfg.Accounts[i].PromptArgs = da.PromptArgs.Clone()
+		}
 	}
 }
 
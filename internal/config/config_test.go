@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -314,6 +315,32 @@ func TestSaveAndLoadV4(t *testing.T) {
 	}
 }
 
+func TestTerminalRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	cfg := NewDefaultConfig("/test")
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if contains(string(data), "terminal:") {
+		t.Error("expected terminal to be omitted when unset")
+	}
+
+	cfg.Terminal = "kitty"
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Terminal != "kitty" {
+		t.Errorf("expected terminal 'kitty', got %q", loaded.Terminal)
+	}
+}
+
 func TestLoadFallbackToLegacy(t *testing.T) {
 	// Create a temp directory structure simulating home
 	dir := t.TempDir()
@@ -738,3 +765,53 @@ func containsPath(path, segment string) bool {
 	}
 	return false
 }
+
+func TestWindowAssignmentRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg := NewDefaultConfig("/dev")
+	cfg.Monitors = []MonitorConfig{{
+		Layout: "vertical",
+		Windows: []WindowConfig{
+			{Tool: "codex", Project: "api", Args: []string{"--model", "o3"}, Title: "api-review"},
+			{Tool: "claude"},
+		},
+	}}
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Count(string(data), "project:") != 1 {
+		t.Errorf("expected project to be omitted for unassigned windows:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	w := loaded.Monitors[0].WindowAt(0)
+	if w.Project != "api" || w.Title != "api-review" || len(w.Args) != 2 {
+		t.Errorf("window assignment not preserved: %+v", w)
+	}
+	if got := loaded.Monitors[0].WindowAt(5); got.Project != "" {
+		t.Errorf("expected zero WindowConfig out of range, got %+v", got)
+	}
+}
+
+func TestWorktreeRoot(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"", filepath.Join(home, ".qs", "worktrees")},
+		{"~/wt", filepath.Join(home, "wt")},
+		{filepath.Join("srv", "wt"), filepath.Join("srv", "wt")},
+	}
+	for _, tt := range tests {
+		cfg := &Config{WorktreeDir: tt.dir}
+		if got := cfg.WorktreeRoot(); got != tt.want {
+			t.Errorf("WorktreeRoot(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseDotenv parses a dotenv file: NAME=value lines, optionally prefixed
+// with export. Blank lines and lines starting with # are skipped. Values
+// may be single-quoted (taken literally) or double-quoted (\n, \t, \" and
+// \\ are unescaped); unquoted values end at a " #" comment.
+func ParseDotenv(data []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected NAME=value", i+1)
+		}
+		name = strings.TrimSpace(name)
+		if err := ValidateEnvVarName(name); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		value, err := dotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+func dotenvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	switch quote := raw[0]; quote {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quote")
+		}
+		return raw[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			c := raw[i]
+			switch {
+			case c == '"':
+				return b.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(raw[i])
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quote")
+	}
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	return raw, nil
+}
+
+// FormatDotenv writes vars as a dotenv file that ParseDotenv reads back,
+// sorted by name.
+func FormatDotenv(vars map[string]string) []byte {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=\"%s\"\n", name, replacer.Replace(vars[name]))
+	}
+	return []byte(b.String())
+}
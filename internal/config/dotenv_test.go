@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	data := []byte(`# provisioned keys
+OPENAI_API_KEY=sk-plain
+export ANTHROPIC_API_KEY = "sk-quoted"
+SINGLE='kept # \\n literal'
+ESCAPED="line\nnext \"q\" \\"
+WITH_COMMENT=value # trailing comment
+EMPTY=
+REF=env:OTHER_VAR
+`)
+	got, err := ParseDotenv(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"OPENAI_API_KEY":    "sk-plain",
+		"ANTHROPIC_API_KEY": "sk-quoted",
+		"SINGLE":            `kept # \\n literal`,
+		"ESCAPED":           "line\nnext \"q\" \\",
+		"WITH_COMMENT":      "value",
+		"EMPTY":             "",
+		"REF":               "env:OTHER_VAR",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDotenv =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	for _, data := range []string{
+		"NO_EQUALS",
+		"BAD NAME=x",
+		`OPEN="unterminated`,
+		"OPEN='unterminated",
+	} {
+		if _, err := ParseDotenv([]byte(data)); err == nil {
+			t.Errorf("ParseDotenv(%q) succeeded", data)
+		}
+	}
+}
+
+func TestFormatDotenvRoundTrip(t *testing.T) {
+	vars := map[string]string{
+		"A": "plain",
+		"B": "with \"quotes\" and \\ and # hash",
+		"C": "multi\nline\ttab",
+		"D": "",
+	}
+	got, err := ParseDotenv(FormatDotenv(vars))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, vars) {
+		t.Errorf("round trip = %v, want %v", got, vars)
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"path"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// EnvPolicy decides which variables of the environment qs runs in a
+// launched tool inherits. With neither list set, it inherits all of them.
+// Patterns are globs such as "AWS_*". Variables qs sets itself, from the
+// keys file or project env, are always passed.
+type EnvPolicy struct {
+	Allow []string `yaml:"allow,omitempty"` // when set, only these and BaseEnv are inherited
+	Deny  []string `yaml:"deny,omitempty"`  // never inherited, even if allowed or in BaseEnv
+}
+
+// Clone returns a copy of p that shares no slices with it.
+func (p EnvPolicy) Clone() EnvPolicy {
+	return EnvPolicy{Allow: slices.Clone(p.Allow), Deny: slices.Clone(p.Deny)}
+}
+
+// BaseEnv are the variables an allowlist always keeps, since programs and
+// terminals rarely work without them.
+var BaseEnv = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TMPDIR", "TZ",
+	"TERM", "COLORTERM", "TERM_PROGRAM", "LANG", "LANGUAGE", "LC_*",
+	"DISPLAY", "WAYLAND_DISPLAY", "XAUTHORITY", "XDG_*", "DBUS_SESSION_BUS_ADDRESS", "SSH_AUTH_SOCK",
+	// Windows
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "OS", "TEMP", "TMP",
+	"USERPROFILE", "USERNAME", "USERDOMAIN", "HOMEDRIVE", "HOMEPATH",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES*", "COMMONPROGRAMFILES*",
+	"PROCESSOR_*", "NUMBER_OF_PROCESSORS",
+}
+
+// Inherits reports whether a variable of the parent environment is passed
+// on under the policy.
+func (p EnvPolicy) Inherits(name string) bool {
+	if matchEnvName(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchEnvName(p.Allow, name) || matchEnvName(BaseEnv, name)
+}
+
+// matchEnvName reports whether name matches one of patterns. Env names are
+// case-insensitive on Windows.
+func matchEnvName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if runtime.GOOS == "windows" {
+			pattern, name = strings.ToUpper(pattern), strings.ToUpper(name)
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// envName returns the name of a NAME=value entry, as compared on this OS.
+func envName(entry string) string {
+	name, _, _ := strings.Cut(entry, "=")
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// DroppedEnv returns the names of the variables in environ the policy
+// doesn't pass on.
+func DroppedEnv(environ []string, policy EnvPolicy) []string {
+	var dropped []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		// Windows keeps per-drive directories in "=C:"-style entries
+		if name != "" && !policy.Inherits(name) {
+			dropped = append(dropped, name)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// BuildEnv returns the environment of a launched tool: the variables of
+// environ the policy passes on, then set, which replaces inherited
+// variables of the same name rather than duplicating them.
+func BuildEnv(environ []string, policy EnvPolicy, set map[string]string) []string {
+	replaced := make(map[string]bool, len(set))
+	for name := range set {
+		replaced[envName(name)] = true
+	}
+	env := make([]string, 0, len(environ)+len(set))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if replaced[envName(entry)] || name != "" && !policy.Inherits(name) {
+			continue
+		}
+		env = append(env, entry)
+	}
+	for _, name := range sortedNames(set) {
+		env = append(env, name+"="+set[name])
+	}
+	return env
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvPolicyInherits(t *testing.T) {
+	tests := []struct {
+		policy EnvPolicy
+		name   string
+		want   bool
+	}{
+		{EnvPolicy{}, "OPENAI_API_KEY", true},
+		{EnvPolicy{Deny: []string{"*_API_KEY"}}, "OPENAI_API_KEY", false},
+		{EnvPolicy{Deny: []string{"*_API_KEY"}}, "EDITOR", true},
+		{EnvPolicy{Allow: []string{"ANTHROPIC_*"}}, "ANTHROPIC_BASE_URL", true},
+		{EnvPolicy{Allow: []string{"ANTHROPIC_*"}}, "OPENAI_API_KEY", false},
+		{EnvPolicy{Allow: []string{"ANTHROPIC_*"}}, "PATH", true}, // BaseEnv
+		{EnvPolicy{Allow: []string{"ANTHROPIC_*"}}, "LC_ALL", true},
+		{EnvPolicy{Allow: []string{"ANTHROPIC_*"}, Deny: []string{"ANTHROPIC_API_KEY"}}, "ANTHROPIC_API_KEY", false},
+		{EnvPolicy{Allow: []string{"X"}, Deny: []string{"SSH_AUTH_SOCK"}}, "SSH_AUTH_SOCK", false},
+		{EnvPolicy{Deny: []string{"[bad"}}, "OPENAI_API_KEY", true},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Inherits(tt.name); got != tt.want {
+			t.Errorf("%+v.Inherits(%s) = %v, want %v", tt.policy, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"OPENAI_API_KEY=sk-shell",
+		"ANTHROPIC_API_KEY=sk-shell-anthropic",
+		"EDITOR=vim",
+	}
+	policy := EnvPolicy{Deny: []string{"OPENAI_*"}}
+	set := map[string]string{"ANTHROPIC_API_KEY": "sk-qs", "CLAUDE_CODE_EFFORT_LEVEL": "max"}
+
+	got := strings.Join(BuildEnv(environ, policy, set), " ")
+	want := "PATH=/usr/bin EDITOR=vim ANTHROPIC_API_KEY=sk-qs CLAUDE_CODE_EFFORT_LEVEL=max"
+	if got != want {
+		t.Errorf("BuildEnv =\n%s\nwant\n%s", got, want)
+	}
+
+	// Inheriting everything still replaces rather than duplicates
+	got = strings.Join(BuildEnv(environ, EnvPolicy{}, set), " ")
+	if strings.Count(got, "ANTHROPIC_API_KEY=") != 1 || !strings.Contains(got, "OPENAI_API_KEY=sk-shell") {
+		t.Errorf("BuildEnv with no policy = %s", got)
+	}
+
+	dropped := DroppedEnv(environ, EnvPolicy{Allow: []string{"EDITOR"}})
+	if strings.Join(dropped, " ") != "ANTHROPIC_API_KEY OPENAI_API_KEY" {
+		t.Errorf("DroppedEnv = %v", dropped)
+	}
+}
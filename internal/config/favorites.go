@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FavoriteKey normalises a project path relative to ProjectsRoot into the
+// form stored in Favorites: cleaned, with forward slashes.
+func FavoriteKey(rel string) string {
+	return path.Clean(filepath.ToSlash(rel))
+}
+
+// IsFavorite reports whether the project at rel (relative to ProjectsRoot)
+// is pinned.
+func (c *Config) IsFavorite(rel string) bool {
+	key := FavoriteKey(rel)
+	for _, f := range c.Favorites {
+		if FavoriteKey(f) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite pins the project at rel, or unpins it if it already is.
+// It returns whether the project is pinned afterwards.
+func (c *Config) ToggleFavorite(rel string) bool {
+	key := FavoriteKey(rel)
+	for i, f := range c.Favorites {
+		if FavoriteKey(f) == key {
+			c.Favorites = append(c.Favorites[:i], c.Favorites[i+1:]...)
+			return false
+		}
+	}
+	c.Favorites = append(c.Favorites, key)
+	return true
+}
+
+// FavoriteByName returns the favorite whose path or last path element is
+// name, e.g. "api" for "work/api" or "src:forks/api". Short names must be
+// unique.
+func (c *Config) FavoriteByName(name string) (string, error) {
+	matches := c.favoritesNamed(name)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("favorite %q not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("favorite %q is ambiguous: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// favoritesNamed returns the favorite matching name exactly, or else all
+// favorites whose last path element matches it.
+func (c *Config) favoritesNamed(name string) []string {
+	key := FavoriteKey(name)
+	var matches []string
+	for _, f := range c.Favorites {
+		f = FavoriteKey(f)
+		if f == key {
+			return []string{f}
+		}
+		_, rel := c.splitProjectRef(f)
+		if strings.EqualFold(path.Base(rel), name) {
+			matches = append(matches, f)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,48 @@
+package config
+
+import "testing"
+
+func TestToggleFavorite(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.ToggleFavorite("work/api") {
+		t.Fatal("expected first toggle to pin")
+	}
+	if !cfg.IsFavorite("work/api/") || !cfg.IsFavorite("./work/api") {
+		t.Errorf("expected normalised paths to match, favorites=%v", cfg.Favorites)
+	}
+	if cfg.ToggleFavorite("work/api") {
+		t.Fatal("expected second toggle to unpin")
+	}
+	if len(cfg.Favorites) != 0 {
+		t.Errorf("expected no favorites, got %v", cfg.Favorites)
+	}
+}
+
+func TestFavoriteByName(t *testing.T) {
+	cfg := &Config{Favorites: []string{"work/api", "web", "play/api", "tools/cli"}}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "web", want: "web"},
+		{name: "tools/cli", want: "tools/cli"},
+		{name: "CLI", want: "tools/cli"},
+		{name: "work/api", want: "work/api"},
+		{name: "api", wantErr: true}, // ambiguous
+		{name: "nope", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := cfg.FavoriteByName(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %q", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
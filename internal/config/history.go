@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxHistoryEntries caps the history file; the oldest launches are dropped
+// first. Launches that old contribute almost nothing to frecency anyway.
+const maxHistoryEntries = 1000
+
+// historyLockTimeout is how long a writer waits for another to finish with
+// the history file; a lock older than this was left by a qs that died.
+const historyLockTimeout = 5 * time.Second
+
+// HistoryEntry is one launch: the directory an account was started in.
+type HistoryEntry struct {
+	Path    string    `yaml:"path"`
+	Account string    `yaml:"account"`
+	Time    time.Time `yaml:"time"`
+}
+
+// History is the launch log kept in ~/.qs/history.yaml, oldest first.
+type History struct {
+	Entries []HistoryEntry `yaml:"entries"`
+}
+
+// RecentProject aggregates the history of one directory.
+type RecentProject struct {
+	Path     string
+	Account  string // account used in the latest launch
+	LastUsed time.Time
+	Launches int
+	Score    float64
+}
+
+// HistoryPath returns the path to the history file (~/.qs/history.yaml)
+func HistoryPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".qs", "history.yaml")
+}
+
+// LoadHistory reads the history file. If path is empty, uses HistoryPath().
+// Returns an empty history if the file doesn't exist.
+func LoadHistory(path string) (*History, error) {
+	if path == "" {
+		path = HistoryPath()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &History{}, nil
+		}
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var h History
+	if err := yaml.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("failed to parse history file: %w", err)
+	}
+	return &h, nil
+}
+
+// SaveHistory writes the history file. If path is empty, uses HistoryPath().
+func SaveHistory(h *History, path string) error {
+	if path == "" {
+		path = HistoryPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	// Write through a temp file of its own so a crash can't leave a
+	// truncated history and concurrent writers can't mix their files
+	f, err := os.CreateTemp(filepath.Dir(path), "history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// LockHistory takes the lock that serializes changes to the history file,
+// so launches recorded at the same moment, as by the pickers qs all opens,
+// don't overwrite each other. The returned function releases it.
+func LockHistory() (func(), error) {
+	lock := HistoryPath() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lock), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	deadline := time.Now().Add(historyLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock history file: %w", err)
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > historyLockTimeout {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("history file is locked (remove %s if no qs is running)", lock)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// RecordLaunch appends a launch to the history file, pruning directories
+// that no longer exist. A history file that can't be read is left alone
+// rather than replaced, so its launches aren't lost.
+func RecordLaunch(path, account string) error {
+	unlock, err := LockHistory()
+	if err != nil {
+		return fmt.Errorf("launch not recorded: %w", err)
+	}
+	defer unlock()
+
+	h, err := LoadHistory("")
+	if err != nil {
+		return fmt.Errorf("launch not recorded: %w (fix %s or run qs history clear)", err, HistoryPath())
+	}
+	h.Add(path, account, time.Now())
+	h.Prune()
+	return SaveHistory(h, "")
+}
+
+// Add appends a launch, dropping the oldest entries beyond the cap.
+func (h *History) Add(path, account string, t time.Time) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	h.Entries = append(h.Entries, HistoryEntry{Path: path, Account: account, Time: t})
+	if extra := len(h.Entries) - maxHistoryEntries; extra > 0 {
+		h.Entries = append([]HistoryEntry(nil), h.Entries[extra:]...)
+	}
+}
+
+// Prune removes entries whose directory no longer exists and returns the
+// number of entries removed.
+func (h *History) Prune() int {
+	exists := make(map[string]bool)
+	kept := h.Entries[:0]
+	for _, e := range h.Entries {
+		ok, seen := exists[e.Path]
+		if !seen {
+			info, err := os.Stat(e.Path)
+			ok = err == nil && info.IsDir()
+			exists[e.Path] = ok
+		}
+		if ok {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(h.Entries) - len(kept)
+	h.Entries = kept
+	return removed
+}
+
+// Clear removes all entries.
+func (h *History) Clear() {
+	h.Entries = nil
+}
+
+// Recent returns one RecentProject per directory, highest frecency first.
+// Ties go to the most recently used.
+func (h *History) Recent(now time.Time) []RecentProject {
+	byPath := make(map[string]*RecentProject)
+	var order []*RecentProject
+	for _, e := range h.Entries {
+		rp := byPath[e.Path]
+		if rp == nil {
+			rp = &RecentProject{Path: e.Path}
+			byPath[e.Path] = rp
+			order = append(order, rp)
+		}
+		rp.Launches++
+		rp.Score += FrecencyWeight(now.Sub(e.Time))
+		if !e.Time.Before(rp.LastUsed) {
+			rp.LastUsed = e.Time
+			rp.Account = e.Account
+		}
+	}
+
+	recent := make([]RecentProject, len(order))
+	for i, rp := range order {
+		recent[i] = *rp
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		if recent[i].Score != recent[j].Score {
+			return recent[i].Score > recent[j].Score
+		}
+		return recent[i].LastUsed.After(recent[j].LastUsed)
+	})
+	return recent
+}
+
+// FrecencyWeight is what one launch of the given age adds to a directory's
+// score. Recent launches count for much more than old ones, so a project
+// used a lot last month is overtaken by one used a few times today.
+func FrecencyWeight(age time.Duration) float64 {
+	switch {
+	case age < 4*time.Hour:
+		return 100
+	case age < 24*time.Hour:
+		return 80
+	case age < 7*24*time.Hour:
+		return 60
+	case age < 30*24*time.Hour:
+		return 40
+	case age < 90*24*time.Hour:
+		return 20
+	default:
+		return 10
+	}
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.yaml")
+
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory on missing file: %v", err)
+	}
+	if len(h.Entries) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(h.Entries))
+	}
+
+	now := time.Now().Truncate(time.Second)
+	h.Add(dir, "claude", now)
+	if err := SaveHistory(h, path); err != nil {
+		t.Fatalf("SaveHistory: %v", err)
+	}
+
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if len(loaded.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(loaded.Entries))
+	}
+	e := loaded.Entries[0]
+	if e.Path != dir || e.Account != "claude" || !e.Time.Equal(now) {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestHistoryAddCapsEntries(t *testing.T) {
+	h := &History{}
+	now := time.Now()
+	for i := 0; i < maxHistoryEntries+10; i++ {
+		h.Add("/p", "claude", now.Add(time.Duration(i)*time.Second))
+	}
+	if len(h.Entries) != maxHistoryEntries {
+		t.Fatalf("expected %d entries, got %d", maxHistoryEntries, len(h.Entries))
+	}
+	if want := now.Add(10 * time.Second); !h.Entries[0].Time.Equal(want) {
+		t.Errorf("expected oldest entries dropped, first is %v", h.Entries[0].Time)
+	}
+}
+
+func TestHistoryPrune(t *testing.T) {
+	root := t.TempDir()
+	keep := filepath.Join(root, "keep")
+	gone := filepath.Join(root, "gone")
+	for _, d := range []string{keep, gone} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := &History{}
+	now := time.Now()
+	h.Add(keep, "claude", now)
+	h.Add(gone, "codex", now)
+	h.Add(gone, "codex", now)
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed := h.Prune(); removed != 2 {
+		t.Errorf("expected 2 entries pruned, got %d", removed)
+	}
+	if len(h.Entries) != 1 || h.Entries[0].Path != keep {
+		t.Errorf("expected only %s to remain, got %+v", keep, h.Entries)
+	}
+}
+
+func TestHistoryRecentFrecency(t *testing.T) {
+	now := time.Now()
+	h := &History{}
+
+	// old: used often, but weeks ago
+	for i := 0; i < 4; i++ {
+		h.Add("/old", "claude", now.Add(-60*24*time.Hour))
+	}
+	// fresh: used twice today, most recently with codex
+	h.Add("/fresh", "claude", now.Add(-2*time.Hour))
+	h.Add("/fresh", "codex", now.Add(-time.Hour))
+	// once: a single launch yesterday
+	h.Add("/once", "claude", now.Add(-30*time.Hour))
+
+	recent := h.Recent(now)
+	if len(recent) != 3 {
+		t.Fatalf("expected 3 projects, got %d", len(recent))
+	}
+	var order []string
+	for _, rp := range recent {
+		order = append(order, filepath.ToSlash(rp.Path))
+	}
+	if order[0] != filepath.ToSlash(absPath(t, "/fresh")) {
+		t.Errorf("expected /fresh first, got %v", order)
+	}
+	if order[1] != filepath.ToSlash(absPath(t, "/old")) {
+		t.Errorf("expected /old (4×20) ahead of /once (60), got %v", order)
+	}
+
+	fresh := recent[0]
+	if fresh.Launches != 2 {
+		t.Errorf("expected 2 launches, got %d", fresh.Launches)
+	}
+	if fresh.Account != "codex" {
+		t.Errorf("expected latest account codex, got %q", fresh.Account)
+	}
+	if !fresh.LastUsed.Equal(now.Add(-time.Hour)) {
+		t.Errorf("unexpected last used %v", fresh.LastUsed)
+	}
+}
+
+func TestFrecencyWeightDecays(t *testing.T) {
+	ages := []time.Duration{
+		time.Minute,
+		12 * time.Hour,
+		3 * 24 * time.Hour,
+		14 * 24 * time.Hour,
+		60 * 24 * time.Hour,
+		365 * 24 * time.Hour,
+	}
+	prev := FrecencyWeight(0)
+	for _, age := range ages[1:] {
+		w := FrecencyWeight(age)
+		if w >= prev {
+			t.Errorf("weight for %v (%v) should be below %v", age, w, prev)
+		}
+		prev = w
+	}
+}
+
+func absPath(t *testing.T, p string) string {
+	t.Helper()
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abs
+}
+
+func TestRecordLaunchKeepsUnreadableHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	path := HistoryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := []byte("entries: [unterminated\n")
+	if err := os.WriteFile(path, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RecordLaunch(home, "claude"); err == nil {
+		t.Error("expected RecordLaunch to report the unreadable history")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(corrupt) {
+		t.Errorf("history was overwritten: %q", data)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordLaunch(home, "claude"); err != nil {
+		t.Fatalf("RecordLaunch: %v", err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp")); len(left) > 0 {
+		t.Errorf("temp files left behind: %v", left)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("lock file left behind")
+	}
+}
+
+func TestRecordLaunchConcurrent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	// As from the pickers qs all opens: no launch may be lost
+	const launches = 10
+	var wg sync.WaitGroup
+	for i := 0; i < launches; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := RecordLaunch(home, "claude"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	h, err := LoadHistory("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries) != launches {
+		t.Errorf("expected %d launches recorded, got %d", launches, len(h.Entries))
+	}
+}
+
+func TestLockHistoryBreaksStaleLock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	lock := HistoryPath() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lock), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lock, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * historyLockTimeout)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := LockHistory()
+	if err != nil {
+		t.Fatalf("LockHistory with a stale lock: %v", err)
+	}
+	unlock()
+}
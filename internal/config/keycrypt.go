@@ -0,0 +1,408 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// Encrypted keys files start with encryptedKeysHeader, followed by a YAML
+// envelope holding the AES-256-GCM sealed plaintext keys file. The key is
+// derived from a passphrase with scrypt, or taken from KeysKeyEnv.
+const (
+	encryptedKeysHeader  = "# qs encrypted keys file\n"
+	encryptedKeysVersion = 1
+
+	// KeysKeyEnv holds a base64 32-byte key for files encrypted with
+	// `qs keys encrypt --env-key`.
+	KeysKeyEnv = "QS_KEYS_KEY"
+	// KeysPassphraseEnv supplies the passphrase without prompting.
+	KeysPassphraseEnv = "QS_KEYS_PASSPHRASE"
+
+	kdfScrypt = "scrypt"
+	kdfEnv    = "env"
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	passphraseAttempts = 3
+	sessionKeyTTL      = 12 * time.Hour
+)
+
+// ErrWrongPassphrase is returned when the keys file can't be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase for the keys file")
+
+// PassphrasePrompt asks the user for the keys file passphrase. The CLI
+// sets it to read from the terminal; while nil, an encrypted keys file can
+// only be opened through KeysPassphraseEnv, KeysKeyEnv or a cached session.
+var PassphrasePrompt func(prompt string) (string, error)
+
+// encryptedKeys is the envelope of an encrypted keys file.
+type encryptedKeys struct {
+	Version int    `yaml:"version"`
+	KDF     string `yaml:"kdf"`
+	N       int    `yaml:"n,omitempty"`
+	R       int    `yaml:"r,omitempty"`
+	P       int    `yaml:"p,omitempty"`
+	Salt    string `yaml:"salt,omitempty"`
+	Nonce   string `yaml:"nonce"`
+	Data    string `yaml:"data"`
+}
+
+// keysCipher is an unlocked keys file: the envelope's KDF parameters and
+// the key they produced, reused when the file is saved again.
+type keysCipher struct {
+	env encryptedKeys // Nonce and Data unused
+	key []byte
+}
+
+// unlocked remembers the key once the keys file is opened, so a process
+// asks for the passphrase at most once.
+var unlocked *keysCipher
+
+// isEncryptedKeys reports whether a keys file's contents are encrypted.
+func isEncryptedKeys(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedKeysHeader))
+}
+
+// KeysEncrypted reports whether the keys file on disk is encrypted.
+func KeysEncrypted() (bool, error) {
+	data, err := os.ReadFile(KeysPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isEncryptedKeys(data), nil
+}
+
+// decryptKeys opens an encrypted keys file, unlocking it first if needed.
+func decryptKeys(data []byte) ([]byte, error) {
+	var env encryptedKeys
+	if err := yaml.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted keys file: %w", err)
+	}
+	if env.Version != encryptedKeysVersion {
+		return nil, fmt.Errorf("unsupported encrypted keys file version %d", env.Version)
+	}
+
+	if unlocked != nil && unlocked.matches(env) {
+		if plain, err := unlocked.open(env); err == nil {
+			return plain, nil
+		}
+	}
+	c, plain, err := unlock(env)
+	if err != nil {
+		return nil, err
+	}
+	unlocked = c
+	return plain, nil
+}
+
+// unlock finds the key for env: from KeysKeyEnv, the session cache,
+// KeysPassphraseEnv or PassphrasePrompt, in that order.
+func unlock(env encryptedKeys) (*keysCipher, []byte, error) {
+	if env.KDF == kdfEnv {
+		key, err := envKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		c := &keysCipher{env: env, key: key}
+		plain, err := c.open(env)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s doesn't decrypt the keys file", KeysKeyEnv)
+		}
+		return c, plain, nil
+	}
+	if env.KDF != kdfScrypt {
+		return nil, nil, fmt.Errorf("unsupported keys file kdf %q", env.KDF)
+	}
+
+	if key := loadSessionKey(env); key != nil {
+		c := &keysCipher{env: env, key: key}
+		if plain, err := c.open(env); err == nil {
+			return c, plain, nil
+		}
+		clearSessionKey()
+	}
+
+	tryPassphrase := func(passphrase string) (*keysCipher, []byte, error) {
+		c, err := deriveCipher(env, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		plain, err := c.open(env)
+		if err != nil {
+			return nil, nil, ErrWrongPassphrase
+		}
+		saveSessionKey(env, c.key)
+		return c, plain, nil
+	}
+
+	if passphrase := os.Getenv(KeysPassphraseEnv); passphrase != "" {
+		return tryPassphrase(passphrase)
+	}
+	if PassphrasePrompt == nil {
+		return nil, nil, fmt.Errorf("keys file is encrypted: set %s or run qs in a terminal", KeysPassphraseEnv)
+	}
+	err := ErrWrongPassphrase
+	for i := 0; i < passphraseAttempts && errors.Is(err, ErrWrongPassphrase); i++ {
+		var passphrase string
+		passphrase, err = PassphrasePrompt("Passphrase for " + KeysPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		var c *keysCipher
+		var plain []byte
+		if c, plain, err = tryPassphrase(passphrase); err == nil {
+			return c, plain, nil
+		}
+	}
+	return nil, nil, err
+}
+
+// newCipher makes a cipher for encrypting a keys file: scrypt with a fresh
+// salt over passphrase, or the key in KeysKeyEnv when passphrase is empty.
+func newCipher(passphrase string) (*keysCipher, error) {
+	if passphrase == "" {
+		key, err := envKey()
+		if err != nil {
+			return nil, err
+		}
+		return &keysCipher{env: encryptedKeys{Version: encryptedKeysVersion, KDF: kdfEnv}, key: key}, nil
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return deriveCipher(encryptedKeys{
+		Version: encryptedKeysVersion,
+		KDF:     kdfScrypt,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    base64.StdEncoding.EncodeToString(salt),
+	}, passphrase)
+}
+
+func deriveCipher(env encryptedKeys, passphrase string) (*keysCipher, error) {
+	salt, err := base64.StdEncoding.DecodeString(env.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys file salt: %w", err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, env.N, env.R, env.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive keys file key: %w", err)
+	}
+	env.Nonce, env.Data = "", ""
+	return &keysCipher{env: env, key: key}, nil
+}
+
+// envKey reads the key from KeysKeyEnv.
+func envKey() ([]byte, error) {
+	value := strings.TrimSpace(os.Getenv(KeysKeyEnv))
+	if value == "" {
+		return nil, fmt.Errorf("keys file is encrypted with %s, which is not set", KeysKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 32 bytes, base64-encoded (e.g. openssl rand -base64 32)", KeysKeyEnv)
+	}
+	return key, nil
+}
+
+// matches reports whether env was encrypted with c's key parameters.
+func (c *keysCipher) matches(env encryptedKeys) bool {
+	return c.env.KDF == env.KDF && c.env.Salt == env.Salt && c.env.N == env.N && c.env.R == env.R && c.env.P == env.P
+}
+
+func (c *keysCipher) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c *keysCipher) open(env encryptedKeys) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid keys file nonce")
+	}
+	data, err := base64.StdEncoding.DecodeString(env.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys file data: %w", err)
+	}
+	return aead.Open(nil, nonce, data, []byte(encryptedKeysHeader))
+}
+
+// seal encrypts plain into a complete encrypted keys file.
+func (c *keysCipher) seal(plain []byte) ([]byte, error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	env := c.env
+	env.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	env.Data = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plain, []byte(encryptedKeysHeader)))
+	out, err := yaml.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(encryptedKeysHeader), out...), nil
+}
+
+// EncryptKeys rewrites the keys file encrypted with passphrase, or with
+// the key in KeysKeyEnv when passphrase is empty. An already encrypted
+// file is re-encrypted, e.g. to change the passphrase.
+func EncryptKeys(passphrase string) error {
+	keys, err := LoadKeys()
+	if err != nil {
+		return err
+	}
+	c, err := newCipher(passphrase)
+	if err != nil {
+		return err
+	}
+	clearSessionKey()
+	unlocked = c
+	if c.env.KDF == kdfScrypt {
+		saveSessionKey(c.env, c.key)
+	}
+	return writeKeys(keys, c)
+}
+
+// DecryptKeys rewrites an encrypted keys file as plaintext YAML.
+func DecryptKeys() error {
+	keys, err := LoadKeys()
+	if err != nil {
+		return err
+	}
+	unlocked = nil
+	clearSessionKey()
+	return writeKeys(keys, nil)
+}
+
+// LockKeys forgets the cached passphrase, so the next qs process asks for
+// it again.
+func LockKeys() error {
+	unlocked = nil
+	return clearSessionKey()
+}
+
+// sessionKeyPath is where an unlocked key is cached between qs processes:
+// the user's runtime directory, which is cleared at logout, or a private
+// directory under the temp dir.
+func sessionKeyPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		name := "qs"
+		if u, err := user.Current(); err == nil {
+			name = "qs-" + filepath.Base(u.Username)
+		}
+		dir = filepath.Join(os.TempDir(), name)
+	} else {
+		dir = filepath.Join(dir, "qs")
+	}
+	sum := sha256.Sum256([]byte(KeysPath()))
+	return filepath.Join(dir, "keys-"+hex.EncodeToString(sum[:8])+".session")
+}
+
+// sessionID identifies the login session a cached key belongs to, so
+// another session of the same user asks for the passphrase itself.
+func sessionID() string {
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		return id
+	}
+	return "-"
+}
+
+// saveSessionKey caches key for the login session, for at most
+// sessionKeyTTL. The cache directory must belong to the user and be closed
+// to everyone else, since the temp dir path is predictable; the file is
+// created fresh and renamed into place, so nothing planted there is
+// followed. Failing to cache only means being asked again.
+func saveSessionKey(env encryptedKeys, key []byte) {
+	path := sessionKeyPath()
+	dir := filepath.Dir(path)
+	if err := os.Mkdir(dir, 0700); err != nil && !os.IsExist(err) {
+		return
+	}
+	if !privateDir(dir) {
+		return
+	}
+
+	f, err := os.CreateTemp(dir, "keys-*.tmp")
+	if err != nil {
+		return
+	}
+	expires := time.Now().Add(sessionKeyTTL).Unix()
+	_, err = fmt.Fprintf(f, "%d %s %s %s\n", expires, env.Salt, sessionID(), base64.StdEncoding.EncodeToString(key))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// loadSessionKey returns the cached key for env, if there is an unexpired
+// one for the same salt and login session.
+func loadSessionKey(env encryptedKeys) []byte {
+	path := sessionKeyPath()
+	if !privateDir(filepath.Dir(path)) {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 4 || fields[1] != env.Salt || fields[2] != sessionID() {
+		return nil
+	}
+	expires, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(fields[3])
+	if err != nil || len(key) != 32 {
+		return nil
+	}
+	return key
+}
+
+func clearSessionKey() error {
+	if err := os.Remove(sessionKeyPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// useKeysHome points KeysPath and the session cache at temp dirs and
+// resets the unlock state around the test.
+func useKeysHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv(KeysKeyEnv, "")
+	t.Setenv(KeysPassphraseEnv, "")
+	unlocked = nil
+	PassphrasePrompt = nil
+	t.Cleanup(func() {
+		unlocked = nil
+		PassphrasePrompt = nil
+	})
+}
+
+func TestEncryptKeysRoundTrip(t *testing.T) {
+	useKeysHome(t)
+	if err := SaveKeys(AccountKeys{"codex": {"OPENAI_API_KEY": "sk-secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(KeysPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedKeys(data) || bytes.Contains(data, []byte("sk-secret")) {
+		t.Fatalf("keys file not encrypted:\n%s", data)
+	}
+	if encrypted, _ := KeysEncrypted(); !encrypted {
+		t.Error("KeysEncrypted() = false")
+	}
+
+	// A new process, with neither the in-process nor the session cache
+	unlocked = nil
+	if err := LockKeys(); err != nil {
+		t.Fatal(err)
+	}
+	prompts := 0
+	PassphrasePrompt = func(string) (string, error) {
+		prompts++
+		return "hunter2", nil
+	}
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys["codex"]["OPENAI_API_KEY"] != "sk-secret" {
+		t.Errorf("keys = %v", keys)
+	}
+	if prompts != 1 {
+		t.Errorf("prompted %d times, want 1", prompts)
+	}
+
+	if err := DecryptKeys(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(KeysPath())
+	if isEncryptedKeys(data) || !bytes.Contains(data, []byte("sk-secret")) {
+		t.Errorf("keys file not decrypted:\n%s", data)
+	}
+}
+
+func TestLoadKeysWrongPassphrase(t *testing.T) {
+	useKeysHome(t)
+	if err := SaveKeys(AccountKeys{"codex": {"OPENAI_API_KEY": "sk-secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("right"); err != nil {
+		t.Fatal(err)
+	}
+	unlocked = nil
+	LockKeys()
+
+	prompts := 0
+	PassphrasePrompt = func(string) (string, error) {
+		prompts++
+		return "wrong", nil
+	}
+	if _, err := LoadKeys(); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("err = %v, want ErrWrongPassphrase", err)
+	}
+	if prompts != passphraseAttempts {
+		t.Errorf("prompted %d times, want %d", prompts, passphraseAttempts)
+	}
+
+	// SaveKeys must not overwrite a file it can't unlock
+	if err := SaveKeys(AccountKeys{}); err == nil {
+		t.Error("SaveKeys succeeded on a locked keys file")
+	}
+
+	PassphrasePrompt = nil
+	t.Setenv(KeysPassphraseEnv, "right")
+	if keys, err := LoadKeys(); err != nil || keys["codex"]["OPENAI_API_KEY"] != "sk-secret" {
+		t.Errorf("LoadKeys with %s = %v, %v", KeysPassphraseEnv, keys, err)
+	}
+}
+
+func TestSessionKeySkipsPrompt(t *testing.T) {
+	useKeysHome(t)
+	if err := SaveKeys(AccountKeys{"codex": {"OPENAI_API_KEY": "sk-secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process: only the session cache remains
+	unlocked = nil
+	PassphrasePrompt = func(string) (string, error) {
+		t.Error("prompted despite a cached session key")
+		return "", errors.New("no prompt")
+	}
+	if _, err := LoadKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	unlocked = nil
+	if err := LockKeys(); err != nil {
+		t.Fatal(err)
+	}
+	PassphrasePrompt = nil
+	if _, err := LoadKeys(); err == nil {
+		t.Error("LoadKeys succeeded after LockKeys without a passphrase")
+	}
+}
+
+func TestSessionKeyNeedsPrivateDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory modes don't apply on Windows")
+	}
+	useKeysHome(t)
+	dir := filepath.Dir(sessionKeyPath())
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveKeys(AccountKeys{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sessionKeyPath()); !os.IsNotExist(err) {
+		t.Errorf("session key cached in a directory others can read: %v", err)
+	}
+}
+
+func TestSessionKeyReplacesPlantedLink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	useKeysHome(t)
+	path := sessionKeyPath()
+	if err := os.Mkdir(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(t.TempDir(), "captured")
+	if err := os.WriteFile(target, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveKeys(AccountKeys{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(target); len(data) != 0 {
+		t.Errorf("session key written through a symlink: %q", data)
+	}
+	if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("session key file = %v, %v, want a regular file", info, err)
+	}
+}
+
+func TestSessionKeyStaysInItsSession(t *testing.T) {
+	useKeysHome(t)
+	t.Setenv("XDG_SESSION_ID", "1")
+	if err := SaveKeys(AccountKeys{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	unlocked = nil
+	t.Setenv("XDG_SESSION_ID", "2")
+	if _, err := LoadKeys(); err == nil {
+		t.Error("another login session used the cached key")
+	}
+}
+
+func TestSaveKeysKeepsEncryption(t *testing.T) {
+	useKeysHome(t)
+	if err := SaveKeys(AccountKeys{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(KeysPath())
+
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetAccountKey(keys, "codex", "OPENAI_API_KEY", "sk-new")
+	if err := SaveKeys(keys); err != nil {
+		t.Fatal(err)
+	}
+
+	after, _ := os.ReadFile(KeysPath())
+	if !isEncryptedKeys(after) || bytes.Contains(after, []byte("sk-new")) {
+		t.Fatalf("SaveKeys dropped the encryption:\n%s", after)
+	}
+	if bytes.Equal(before, after) {
+		t.Error("keys file unchanged")
+	}
+	unlocked = nil
+	t.Setenv(KeysPassphraseEnv, "hunter2")
+	LockKeys()
+	keys, err = LoadKeys()
+	if err != nil || keys["codex"]["OPENAI_API_KEY"] != "sk-new" {
+		t.Errorf("LoadKeys = %v, %v", keys, err)
+	}
+}
+
+func TestEncryptKeysWithEnvKey(t *testing.T) {
+	useKeysHome(t)
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	t.Setenv(KeysKeyEnv, key)
+	if err := SaveKeys(AccountKeys{"codex": {"OPENAI_API_KEY": "sk-secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptKeys(""); err != nil {
+		t.Fatal(err)
+	}
+
+	unlocked = nil
+	if keys, err := LoadKeys(); err != nil || keys["codex"]["OPENAI_API_KEY"] != "sk-secret" {
+		t.Errorf("LoadKeys = %v, %v", keys, err)
+	}
+
+	unlocked = nil
+	t.Setenv(KeysKeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+	if _, err := LoadKeys(); err == nil {
+		t.Error("LoadKeys succeeded with the wrong key")
+	}
+	t.Setenv(KeysKeyEnv, "")
+	if _, err := LoadKeys(); err == nil {
+		t.Error("LoadKeys succeeded without the key")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package config
+
+import "os"
+
+// privateDir reports whether dir is a real directory, not a symlink. The
+// temp dir on Windows is inside the user's profile, which its ACLs already
+// keep from other users.
+func privateDir(dir string) bool {
+	info, err := os.Lstat(dir)
+	return err == nil && info.IsDir()
+}
@@ -20,6 +20,7 @@ func KeysPath() string {
 }
 
 // LoadKeys reads the keys file. Returns an empty map if the file doesn't exist.
+// An encrypted keys file is decrypted, asking for its passphrase if needed.
 func LoadKeys() (AccountKeys, error) {
 	data, err := os.ReadFile(KeysPath())
 	if err != nil {
@@ -28,6 +29,11 @@ func LoadKeys() (AccountKeys, error) {
 		}
 		return nil, fmt.Errorf("failed to read keys file: %w", err)
 	}
+	if isEncryptedKeys(data) {
+		if data, err = decryptKeys(data); err != nil {
+			return nil, err
+		}
+	}
 
 	var keys AccountKeys
 	if err := yaml.Unmarshal(data, &keys); err != nil {
@@ -41,8 +47,23 @@ func LoadKeys() (AccountKeys, error) {
 	return keys, nil
 }
 
-// SaveKeys writes the keys file with restrictive permissions (0600).
+// SaveKeys writes the keys file with restrictive permissions (0600). An
+// encrypted keys file stays encrypted.
 func SaveKeys(keys AccountKeys) error {
+	var c *keysCipher
+	if data, err := os.ReadFile(KeysPath()); err == nil && isEncryptedKeys(data) {
+		// Unlock first, so keys are never written under a key the file
+		// wasn't encrypted with, nor in plaintext
+		if _, err := decryptKeys(data); err != nil {
+			return err
+		}
+		c = unlocked
+	}
+	return writeKeys(keys, c)
+}
+
+// writeKeys writes the keys file, encrypted with c unless it's nil.
+func writeKeys(keys AccountKeys, c *keysCipher) error {
 	path := KeysPath()
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -53,6 +74,11 @@ func SaveKeys(keys AccountKeys) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal keys: %w", err)
 	}
+	if c != nil {
+		if data, err = c.seal(data); err != nil {
+			return fmt.Errorf("failed to encrypt keys: %w", err)
+		}
+	}
 
 	if err := os.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("failed to write keys file: %w", err)
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Profile is a named monitor/window/tool arrangement. `qs all --profile <name>`
+// launches it in place of the top-level Monitors.
+type Profile struct {
+	Name     string          `yaml:"name"`
+	Monitors []MonitorConfig `yaml:"monitors"`
+}
+
+// ValidateProfileName checks that name is usable as a profile name: non-empty
+// and made of letters, digits, '-' or '_' so it works as a CLI argument and
+// in a tmux session name, where '.' and ':' separate windows and panes.
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_':
+		default:
+			return fmt.Errorf("invalid profile name %q: use letters, digits, '-' or '_'", name)
+		}
+	}
+	return nil
+}
+
+// ProfileByName returns the profile with the given name (case-insensitive), or nil.
+func (c *Config) ProfileByName(name string) *Profile {
+	for i := range c.Profiles {
+		if strings.EqualFold(c.Profiles[i].Name, name) {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// ProfileNames returns the names of all profiles in config order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, len(c.Profiles))
+	for i, p := range c.Profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// UseProfile replaces Monitors with a copy of the named profile's arrangement.
+// The change is in memory only; callers that launch a profile must not Save.
+func (c *Config) UseProfile(name string) error {
+	p := c.ProfileByName(name)
+	if p == nil {
+		return c.unknownProfile(name)
+	}
+	c.Monitors = CopyMonitors(p.Monitors)
+	return nil
+}
+
+// AddProfile stores a copy of monitors as a new profile.
+func (c *Config) AddProfile(name string, monitors []MonitorConfig) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if c.ProfileByName(name) != nil {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	c.Profiles = append(c.Profiles, Profile{Name: name, Monitors: CopyMonitors(monitors)})
+	return nil
+}
+
+// DeleteProfile removes the named profile.
+func (c *Config) DeleteProfile(name string) error {
+	for i := range c.Profiles {
+		if strings.EqualFold(c.Profiles[i].Name, name) {
+			c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+			return nil
+		}
+	}
+	return c.unknownProfile(name)
+}
+
+// RenameProfile changes the name of an existing profile.
+func (c *Config) RenameProfile(oldName, newName string) error {
+	p := c.ProfileByName(oldName)
+	if p == nil {
+		return c.unknownProfile(oldName)
+	}
+	if err := ValidateProfileName(newName); err != nil {
+		return err
+	}
+	if other := c.ProfileByName(newName); other != nil && other != p {
+		return fmt.Errorf("profile %q already exists", newName)
+	}
+	p.Name = newName
+	return nil
+}
+
+func (c *Config) unknownProfile(name string) error {
+	if len(c.Profiles) == 0 {
+		return fmt.Errorf("profile %q not found (no profiles configured — create one with qs profiles create)", name)
+	}
+	return fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+}
+
+// CopyMonitors returns a deep copy of a monitor arrangement.
+func CopyMonitors(monitors []MonitorConfig) []MonitorConfig {
+	if monitors == nil {
+		return nil
+	}
+	out := make([]MonitorConfig, len(monitors))
+	for i, mc := range monitors {
+		out[i] = MonitorConfig{Layout: mc.Layout}
+		for _, w := range mc.Windows {
+			w.Args = append([]string(nil), w.Args...)
+			out[i].Windows = append(out[i].Windows, w)
+		}
+	}
+	return out
+}
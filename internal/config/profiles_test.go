@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileLifecycle(t *testing.T) {
+	cfg := NewDefaultConfig("/test")
+	review := []MonitorConfig{
+		{Layout: "grid", Windows: []WindowConfig{{Tool: "claude"}, {Tool: "codex"}, {Tool: "claude"}}},
+		{Layout: "full", Windows: []WindowConfig{{Tool: "claude"}}},
+	}
+
+	if err := cfg.AddProfile("review", review); err != nil {
+		t.Fatalf("AddProfile failed: %v", err)
+	}
+	if err := cfg.AddProfile("Review", review); err == nil {
+		t.Error("expected duplicate profile name (case-insensitive) to fail")
+	}
+	for _, name := range []string{"has space", "work.laptop", "work:laptop"} {
+		if err := cfg.AddProfile(name, review); err == nil {
+			t.Errorf("expected invalid profile name %q to fail", name)
+		}
+	}
+
+	// Profiles hold a copy, not the caller's slice
+	review[0].Windows[1].Tool = "gemini"
+	if got := cfg.ProfileByName("review").Monitors[0].ToolFor(1); got != "codex" {
+		t.Errorf("profile shares storage with caller: tool = %q", got)
+	}
+
+	if err := cfg.RenameProfile("review", "full-review"); err != nil {
+		t.Fatalf("RenameProfile failed: %v", err)
+	}
+	if cfg.ProfileByName("review") != nil {
+		t.Error("old name still resolves after rename")
+	}
+
+	if err := cfg.UseProfile("full-review"); err != nil {
+		t.Fatalf("UseProfile failed: %v", err)
+	}
+	if len(cfg.Monitors) != 2 || cfg.Monitors[0].WindowCount() != 3 {
+		t.Errorf("UseProfile did not apply arrangement: %+v", cfg.Monitors)
+	}
+
+	if err := cfg.UseProfile("missing"); err == nil {
+		t.Error("expected UseProfile of unknown profile to fail")
+	}
+
+	if err := cfg.DeleteProfile("full-review"); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if len(cfg.Profiles) != 0 {
+		t.Errorf("expected no profiles, got %d", len(cfg.Profiles))
+	}
+	if err := cfg.DeleteProfile("full-review"); err == nil {
+		t.Error("expected deleting a missing profile to fail")
+	}
+}
+
+func TestProfilesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg := NewDefaultConfig("/test")
+	cfg.AddProfile("focus", []MonitorConfig{{Layout: "full", Windows: []WindowConfig{{Tool: "claude"}}}})
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	p := loaded.ProfileByName("focus")
+	if p == nil {
+		t.Fatal("profile not persisted")
+	}
+	if len(p.Monitors) != 1 || p.Monitors[0].Layout != "full" {
+		t.Errorf("unexpected profile monitors: %+v", p.Monitors)
+	}
+}
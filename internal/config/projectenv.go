@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectSettings are per-project settings, listed under projects: in the
+// config. Env files are opt-in: a project's .qs.env or .env is only read
+// when EnvFiles names it, so cloning a repository can't change what qs
+// injects.
+type ProjectSettings struct {
+	Project  string            `yaml:"project"`            // a project reference as --project takes, or a path
+	EnvFiles []string          `yaml:"envFiles,omitempty"` // dotenv files in the project read at launch, e.g. [.qs.env]
+	Env      map[string]string `yaml:"env,omitempty"`      // values may be secret references
+}
+
+// Env var sources, lowest precedence first: a project's settings override
+// its env files, which override the account's keys. The environment qs
+// was started in is below all of them.
+const (
+	EnvSourceAccount = "account"
+	EnvSourceConfig  = "config"
+)
+
+// EnvVar is a variable set for a launch. Value is as written; references
+// are resolved by ResolveEnv.
+type EnvVar struct {
+	Name   string
+	Value  string
+	Source string // EnvSourceAccount, EnvSourceConfig or an env file's name
+
+	literal bool // read from an env file, so never resolved
+}
+
+// ProjectSettingsFor returns the settings of the project containing dir,
+// the innermost one if projects are nested, or nil.
+func (c *Config) ProjectSettingsFor(dir string) *ProjectSettings {
+	dir = filepath.Clean(dir)
+	var best *ProjectSettings
+	bestLen := -1
+	for i := range c.Projects {
+		p := &c.Projects[i]
+		projectDir := c.projectSettingsDir(p.Project)
+		if projectDir == "" || len(projectDir) <= bestLen {
+			continue
+		}
+		if rel, err := filepath.Rel(projectDir, dir); err == nil && filepath.IsLocal(rel) {
+			best, bestLen = p, len(projectDir)
+		}
+	}
+	return best
+}
+
+// projectSettingsDir resolves a ProjectSettings.Project to a directory, or
+// "" if it doesn't name one.
+func (c *Config) projectSettingsDir(ref string) string {
+	if path := expandHome(ref); filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	dir, err := c.ResolveProject(ref)
+	if err != nil {
+		return ""
+	}
+	return filepath.Clean(dir)
+}
+
+// ProjectEnv returns the variables the settings of the project containing
+// dir add: its env files in order, then its env. Missing env files are
+// skipped.
+func ProjectEnv(cfg *Config, dir string) ([]EnvVar, error) {
+	if cfg == nil || dir == "" {
+		return nil, nil
+	}
+	p := cfg.ProjectSettingsFor(dir)
+	if p == nil {
+		return nil, nil
+	}
+	projectDir := cfg.projectSettingsDir(p.Project)
+
+	var vars []EnvVar
+	for _, name := range p.EnvFiles {
+		if !filepath.IsLocal(name) {
+			return nil, fmt.Errorf("env file %q of %s must be inside the project", name, p.Project)
+		}
+		data, err := os.ReadFile(filepath.Join(projectDir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		parsed, err := ParseDotenv(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, k := range sortedNames(parsed) {
+			vars = append(vars, EnvVar{Name: k, Value: parsed[k], Source: name, literal: true})
+		}
+	}
+	for _, k := range sortedNames(p.Env) {
+		vars = append(vars, EnvVar{Name: k, Value: p.Env[k], Source: EnvSourceConfig})
+	}
+	return vars, nil
+}
+
+// AccountEnv returns an account's keys as variables.
+func AccountEnv(keys AccountKeys, accountID string) []EnvVar {
+	ak := KeysForAccount(keys, accountID)
+	vars := make([]EnvVar, 0, len(ak))
+	for _, k := range sortedNames(ak) {
+		vars = append(vars, EnvVar{Name: k, Value: ak[k], Source: EnvSourceAccount})
+	}
+	return vars
+}
+
+// MergeEnv merges lists of variables, later ones overriding earlier ones
+// of the same name, sorted by name.
+func MergeEnv(lists ...[]EnvVar) []EnvVar {
+	byName := make(map[string]EnvVar)
+	for _, list := range lists {
+		for _, v := range list {
+			byName[v.Name] = v
+		}
+	}
+	merged := make([]EnvVar, 0, len(byName))
+	for _, v := range byName {
+		merged = append(merged, v)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
+
+// LaunchEnv returns the variables set when accountID launches in dir: the
+// account's keys overridden by the project's env files and settings. dir
+// may be empty, e.g. for login commands, for the account's keys alone.
+func LaunchEnv(cfg *Config, keys AccountKeys, accountID, dir string) ([]EnvVar, error) {
+	project, err := ProjectEnv(cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+	return MergeEnv(AccountEnv(keys, accountID), project), nil
+}
+
+// ResolveEnv resolves the secret references in vars. Values read from env
+// files are used as written.
+func ResolveEnv(cfg *Config, vars []EnvVar) (map[string]string, error) {
+	if len(vars) == 0 {
+		return nil, nil
+	}
+	env := make(map[string]string, len(vars))
+	for _, v := range vars {
+		if v.literal {
+			env[v.Name] = v.Value
+			continue
+		}
+		value, err := ResolveSecret(cfg, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s from %s: %w", v.Name, v.Source, err)
+		}
+		env[v.Name] = value
+	}
+	return env, nil
+}
+
+// EnvNames describes vars for display: each name with where it comes from,
+// never a value.
+func EnvNames(vars []EnvVar) string {
+	parts := make([]string, len(vars))
+	for i, v := range vars {
+		parts[i] = v.Name
+		if v.Source != EnvSourceAccount {
+			parts[i] += " (" + v.Source + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectSettingsFor(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"api/sub", "web", "mono/pkg/inner"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &Config{
+		Roots: []ProjectRoot{{Name: "dev", Path: root}},
+		Projects: []ProjectSettings{
+			{Project: "api"},
+			{Project: "mono"},
+			{Project: filepath.Join(root, "mono", "pkg")},
+			{Project: "missing"},
+		},
+	}
+	cfg.NormalizeRoots()
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(root, "api"), "api"},
+		{filepath.Join(root, "api", "sub"), "api"},
+		{filepath.Join(root, "mono"), "mono"},
+		{filepath.Join(root, "mono", "pkg", "inner"), filepath.Join(root, "mono", "pkg")},
+		{filepath.Join(root, "web"), ""},
+		{root, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if p := cfg.ProjectSettingsFor(tt.dir); p != nil {
+			got = p.Project
+		}
+		if got != tt.want {
+			t.Errorf("ProjectSettingsFor(%s) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestLaunchEnvPrecedence(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	if err := os.MkdirAll(api, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		".env":    "DATABASE_URL=postgres://localhost/api\nFROM_ENV=env:NOT_RESOLVED\nSHARED=dotenv\n",
+		".qs.env": "OPENAI_API_KEY=sk-client\nSHARED=qs.env\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(api, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("QS_TEST_TOKEN", "from-env")
+	cfg := &Config{
+		Roots: []ProjectRoot{{Name: "dev", Path: root}},
+		Projects: []ProjectSettings{{
+			Project:  "api",
+			EnvFiles: []string{".env", ".qs.env", ".missing.env"},
+			Env:      map[string]string{"TOKEN": "env:QS_TEST_TOKEN", "SHARED": "config"},
+		}},
+	}
+	cfg.NormalizeRoots()
+	keys := AccountKeys{"codex": {"OPENAI_API_KEY": "sk-personal", "ACCOUNT_ONLY": "kept"}}
+
+	vars, err := LaunchEnv(cfg, keys, "codex", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := ResolveEnv(cfg, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"ACCOUNT_ONLY":   "kept",
+		"OPENAI_API_KEY": "sk-client",
+		"DATABASE_URL":   "postgres://localhost/api",
+		"FROM_ENV":       "env:NOT_RESOLVED", // env files are read as written
+		"SHARED":         "config",
+		"TOKEN":          "from-env",
+	}
+	if len(env) != len(want) {
+		t.Errorf("env = %v, want %v", env, want)
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+
+	got := EnvNames(vars)
+	wantNames := "ACCOUNT_ONLY, DATABASE_URL (.env), FROM_ENV (.env), OPENAI_API_KEY (.qs.env), SHARED (config), TOKEN (config)"
+	if got != wantNames {
+		t.Errorf("EnvNames = %q, want %q", got, wantNames)
+	}
+
+	// Outside the project, and without a directory, only the account's keys
+	for _, dir := range []string{root, ""} {
+		vars, err := LaunchEnv(cfg, keys, "codex", dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vars) != 2 || vars[1].Value != "sk-personal" {
+			t.Errorf("LaunchEnv in %q = %+v", dir, vars)
+		}
+	}
+}
+
+func TestProjectEnvRejectsOutsideFiles(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		Roots:    []ProjectRoot{{Name: "dev", Path: root}},
+		Projects: []ProjectSettings{{Project: "api", EnvFiles: []string{"../secrets.env"}}},
+	}
+	cfg.NormalizeRoots()
+	if _, err := ProjectEnv(cfg, filepath.Join(root, "api")); err == nil {
+		t.Error("ProjectEnv read an env file outside the project")
+	}
+}
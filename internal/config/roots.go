@@ -0,0 +1,336 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveProject's errors wrap these, so callers can tell a missing
+// project from an ambiguous one.
+var (
+	ErrProjectNotFound  = errors.New("not found")
+	ErrAmbiguousProject = errors.New("ambiguous")
+)
+
+// ProjectRoot is a named directory of project folders. The first root in
+// Config.Roots is the primary one, mirrored in Config.ProjectsRoot.
+type ProjectRoot struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+// ValidateRootName checks that a root name can be used in root:project
+// references: letters, digits, '-', '_' and '.' only.
+func ValidateRootName(name string) error {
+	if name == "" {
+		return fmt.Errorf("root name cannot be empty")
+	}
+	for _, r := range name {
+		if !isRootNameRune(r) {
+			return fmt.Errorf("root name %q may only contain letters, digits, '-', '_' and '.'", name)
+		}
+	}
+	return nil
+}
+
+func isRootNameRune(r rune) bool {
+	return r == '-' || r == '_' || r == '.' ||
+		(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// DefaultRootName derives a root name from its path: the last path element
+// without leading dots, e.g. "1dev" for ~/.1dev.
+func DefaultRootName(path string) string {
+	name := strings.TrimLeft(filepath.Base(filepath.Clean(path)), ".")
+	name = strings.Map(func(r rune) rune {
+		if isRootNameRune(r) {
+			return r
+		}
+		return '-'
+	}, name)
+	if name == "" || name == "-" {
+		return "projects"
+	}
+	return name
+}
+
+// ProjectRoots returns the configured roots. Configs written before roots
+// existed get their single ProjectsRoot as the only root.
+func (c *Config) ProjectRoots() []ProjectRoot {
+	if len(c.Roots) > 0 {
+		return c.Roots
+	}
+	if root := strings.TrimSpace(c.ProjectsRoot); root != "" {
+		return []ProjectRoot{{Name: DefaultRootName(root), Path: root}}
+	}
+	return nil
+}
+
+// NormalizeRoots migrates a single-root config to Roots and keeps
+// ProjectsRoot in sync with the primary root. Duplicate names get a numeric
+// suffix so every root stays addressable.
+func (c *Config) NormalizeRoots() {
+	roots := c.ProjectRoots()
+	if len(roots) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	normalized := make([]ProjectRoot, 0, len(roots))
+	for _, r := range roots {
+		r.Path = expandHome(strings.TrimSpace(r.Path))
+		if r.Path == "" {
+			continue
+		}
+		if ValidateRootName(r.Name) != nil {
+			r.Name = DefaultRootName(r.Path)
+		}
+		name := r.Name
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d", r.Name, n)
+		}
+		r.Name = name
+		seen[strings.ToLower(name)] = true
+		normalized = append(normalized, r)
+	}
+	if len(normalized) == 0 {
+		return
+	}
+	c.Roots = normalized
+	c.ProjectsRoot = normalized[0].Path
+}
+
+// SetProjectsRoot changes the primary root's path.
+func (c *Config) SetProjectsRoot(path string) {
+	c.ProjectsRoot = path
+	if len(c.Roots) > 0 {
+		c.Roots[0].Path = path
+	}
+}
+
+// RootByName returns the root with the given name (case-insensitive), or nil.
+func (c *Config) RootByName(name string) *ProjectRoot {
+	for i := range c.Roots {
+		if strings.EqualFold(c.Roots[i].Name, name) {
+			return &c.Roots[i]
+		}
+	}
+	return nil
+}
+
+// AddRoot appends a named root. An empty name is derived from the path,
+// with a numeric suffix if another root already uses it. Relative paths are
+// made absolute.
+func (c *Config) AddRoot(name, path string) error {
+	path = expandHome(strings.TrimSpace(path))
+	if path == "" {
+		return fmt.Errorf("root path cannot be empty")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid root path: %w", err)
+	}
+	c.NormalizeRoots()
+	if name == "" {
+		base := DefaultRootName(path)
+		name = base
+		for n := 2; c.RootByName(name) != nil; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+	}
+	if err := ValidateRootName(name); err != nil {
+		return err
+	}
+	if c.RootByName(name) != nil {
+		return fmt.Errorf("root %q already exists", name)
+	}
+	for _, r := range c.Roots {
+		if filepath.Clean(r.Path) == filepath.Clean(path) {
+			return fmt.Errorf("%s is already the %q root", path, r.Name)
+		}
+	}
+	c.Roots = append(c.Roots, ProjectRoot{Name: name, Path: path})
+	c.ProjectsRoot = c.Roots[0].Path
+	return nil
+}
+
+// RemoveRoot deletes a root by name. The last remaining root can't be
+// removed; removing the primary root promotes the next one.
+//
+// Project references in favorites, monitor and profile windows and
+// project settings are rewritten for the remaining roots, since references
+// to the primary root have no root prefix. Favorites and windows in the
+// removed root are dropped; project settings there keep their absolute
+// path, so their env isn't lost.
+func (c *Config) RemoveRoot(name string) error {
+	c.NormalizeRoots()
+	for i, r := range c.Roots {
+		if strings.EqualFold(r.Name, name) {
+			if len(c.Roots) == 1 {
+				return fmt.Errorf("cannot remove the only project root")
+			}
+			old := *c
+			old.Roots = append([]ProjectRoot(nil), c.Roots...)
+			c.Roots = append(c.Roots[:i], c.Roots[i+1:]...)
+			c.ProjectsRoot = c.Roots[0].Path
+			c.rewriteRefs(&old, r)
+			return nil
+		}
+	}
+	return fmt.Errorf("root %q not found", name)
+}
+
+// rewriteRefs rewrites the project references resolved under old for c's
+// roots after removed was taken out of them.
+func (c *Config) rewriteRefs(old *Config, removed ProjectRoot) {
+	// rewrite returns ref's new form, or false if it was in removed.
+	// Favorites always name a root's folder; other refs are searched for
+	// the way --project does.
+	rewrite := func(ref string, search bool) (string, bool) {
+		if ref == "" || filepath.IsAbs(expandHome(ref)) {
+			return ref, true
+		}
+		dir := old.RefDir(ref)
+		if search {
+			if resolved, err := old.ResolveProject(ref); err == nil {
+				dir = resolved
+			}
+		}
+		if rel, err := filepath.Rel(removed.Path, dir); err == nil && filepath.IsLocal(rel) {
+			return dir, false
+		}
+		return c.ProjectRef(dir), true
+	}
+
+	var favorites []string
+	for _, f := range c.Favorites {
+		if ref, ok := rewrite(f, false); ok {
+			favorites = append(favorites, ref)
+		}
+	}
+	c.Favorites = favorites
+
+	windows := func(monitors []MonitorConfig) {
+		for i := range monitors {
+			for j := range monitors[i].Windows {
+				w := &monitors[i].Windows[j]
+				if ref, ok := rewrite(w.Project, true); ok {
+					w.Project = ref
+				} else {
+					w.Project = ""
+				}
+			}
+		}
+	}
+	windows(c.Monitors)
+	for i := range c.Profiles {
+		windows(c.Profiles[i].Monitors)
+	}
+
+	for i := range c.Projects {
+		// In the removed root the absolute path is returned, which keeps
+		// the settings
+		c.Projects[i].Project, _ = rewrite(c.Projects[i].Project, true)
+	}
+}
+
+// splitProjectRef splits "root:path" into its root and path when the prefix
+// names a configured root. Anything else, including Windows drive paths, is
+// returned as a plain path.
+func (c *Config) splitProjectRef(ref string) (*ProjectRoot, string) {
+	if name, rest, ok := strings.Cut(ref, ":"); ok {
+		if root := c.RootByName(name); root != nil {
+			return root, rest
+		}
+	}
+	return nil, ref
+}
+
+// ProjectRef returns how a project directory is referred to in favorites
+// and root:project arguments: its path relative to the primary root, or
+// "<root>:<path>" for the other roots. Directories outside every root are
+// returned unchanged.
+func (c *Config) ProjectRef(dir string) string {
+	for i, r := range c.ProjectRoots() {
+		rel, err := filepath.Rel(r.Path, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if i == 0 {
+			return rel
+		}
+		return r.Name + ":" + rel
+	}
+	return dir
+}
+
+// RefDir returns the directory a project reference points at, without
+// checking that it exists: root:path under that root, anything else under
+// the primary root.
+func (c *Config) RefDir(ref string) string {
+	if root, rel := c.splitProjectRef(ref); root != nil {
+		return filepath.Join(root.Path, filepath.FromSlash(rel))
+	}
+	if filepath.IsAbs(ref) {
+		return filepath.Clean(ref)
+	}
+	return filepath.Join(c.ProjectsRoot, filepath.FromSlash(ref))
+}
+
+// ResolveProject returns the directory for a project reference: root:name,
+// a path under any root (the first root containing it wins) or a favorite's
+// short name.
+func (c *Config) ResolveProject(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", fmt.Errorf("project name is required")
+	}
+
+	if root, rel := c.splitProjectRef(ref); root != nil {
+		dir := c.RefDir(ref)
+		if !isDir(dir) {
+			return "", fmt.Errorf("project %q %w in %s", rel, ErrProjectNotFound, root.Path)
+		}
+		return dir, nil
+	}
+
+	for _, r := range c.ProjectRoots() {
+		if dir := filepath.Join(r.Path, filepath.FromSlash(ref)); isDir(dir) {
+			return dir, nil
+		}
+	}
+
+	switch favs := c.favoritesNamed(ref); {
+	case len(favs) == 1 && favs[0] != FavoriteKey(ref):
+		return c.ResolveProject(favs[0])
+	case len(favs) > 1:
+		return "", fmt.Errorf("project %q is %w, it matches several favorites: %s", ref, ErrAmbiguousProject, strings.Join(favs, ", "))
+	}
+
+	var paths []string
+	for _, r := range c.ProjectRoots() {
+		paths = append(paths, r.Path)
+	}
+	return "", fmt.Errorf("project %q %w in %s", ref, ErrProjectNotFound, strings.Join(paths, ", "))
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// expandHome expands a leading ~ to the user's home directory.
+func expandHome(path string) string {
+	homeDir, _ := os.UserHomeDir()
+	switch {
+	case path == "~":
+		return homeDir
+	case strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`):
+		return filepath.Join(homeDir, path[2:])
+	}
+	return path
+}
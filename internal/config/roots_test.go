@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigratesSingleRoot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := "version: 4\nprojectsRoot: /home/me/.1dev\naccounts: []\nmonitors: []\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Roots) != 1 || cfg.Roots[0].Name != "1dev" || cfg.Roots[0].Path != "/home/me/.1dev" {
+		t.Fatalf("expected single root named 1dev, got %+v", cfg.Roots)
+	}
+	if cfg.ProjectsRoot != "/home/me/.1dev" {
+		t.Errorf("ProjectsRoot changed: %q", cfg.ProjectsRoot)
+	}
+
+	// Round trip keeps both fields
+	if err := Save(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Roots) != 1 || loaded.ProjectsRoot != cfg.ProjectsRoot {
+		t.Errorf("unexpected roots after round trip: %+v / %q", loaded.Roots, loaded.ProjectsRoot)
+	}
+}
+
+func TestLoadRootsSetPrimary(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `version: 4
+projectsRoot: /stale
+roots:
+  - name: work
+    path: /home/me/work
+  - name: work
+    path: /home/me/src
+accounts: []
+monitors: []
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.ProjectsRoot != "/home/me/work" {
+		t.Errorf("expected primary root from roots, got %q", cfg.ProjectsRoot)
+	}
+	if cfg.Roots[1].Name != "work-2" {
+		t.Errorf("expected duplicate name suffixed, got %q", cfg.Roots[1].Name)
+	}
+}
+
+func TestAddRemoveRoot(t *testing.T) {
+	cfg := &Config{ProjectsRoot: "/home/me/.1dev"}
+	if err := cfg.AddRoot("work", "/home/me/work"); err != nil {
+		t.Fatalf("AddRoot: %v", err)
+	}
+	if err := cfg.AddRoot("", "/home/me/src"); err != nil {
+		t.Fatalf("AddRoot with derived name: %v", err)
+	}
+	if len(cfg.Roots) != 3 || cfg.Roots[2].Name != "src" {
+		t.Fatalf("unexpected roots %+v", cfg.Roots)
+	}
+	if err := cfg.AddRoot("WORK", "/elsewhere"); err == nil {
+		t.Error("expected duplicate name to be rejected")
+	}
+	if err := cfg.AddRoot("other", "/home/me/work"); err == nil {
+		t.Error("expected duplicate path to be rejected")
+	}
+	if err := cfg.AddRoot("a:b", "/x"); err == nil {
+		t.Error("expected ':' in a name to be rejected")
+	}
+	if err := cfg.AddRoot("", "/mnt/src"); err != nil || cfg.Roots[3].Name != "src-2" {
+		t.Errorf("expected derived name to be suffixed, got %v / %+v", err, cfg.Roots)
+	}
+	cfg.RemoveRoot("src-2")
+
+	if err := cfg.RemoveRoot("1dev"); err != nil {
+		t.Fatalf("RemoveRoot: %v", err)
+	}
+	if want, _ := filepath.Abs("/home/me/work"); cfg.ProjectsRoot != want {
+		t.Errorf("expected next root promoted to primary, got %q", cfg.ProjectsRoot)
+	}
+	cfg.RemoveRoot("src")
+	if err := cfg.RemoveRoot("work"); err == nil {
+		t.Error("expected removing the last root to fail")
+	}
+}
+
+func TestAddRootMakesPathAbsolute(t *testing.T) {
+	cfg := &Config{ProjectsRoot: t.TempDir()}
+	if err := cfg.AddRoot("src", "src"); err != nil {
+		t.Fatalf("AddRoot: %v", err)
+	}
+	want, _ := filepath.Abs("src")
+	if got := cfg.Roots[1].Path; got != want {
+		t.Errorf("root path = %q, want %q", got, want)
+	}
+}
+
+func TestRemoveRootRewritesRefs(t *testing.T) {
+	dev, work := t.TempDir(), t.TempDir()
+	for _, d := range []string{filepath.Join(dev, "old"), filepath.Join(work, "api")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := filepath.Join(t.TempDir(), "tool")
+	windows := func() []MonitorConfig {
+		return []MonitorConfig{{Windows: []WindowConfig{{Project: "old"}, {Project: "work:api"}, {Project: "api"}, {}}}}
+	}
+	cfg := &Config{
+		Roots:     []ProjectRoot{{Name: "dev", Path: dev}, {Name: "work", Path: work}},
+		Favorites: []string{"old", "work:api", outside},
+		Monitors:  windows(),
+		Profiles:  []Profile{{Name: "p", Monitors: windows()}},
+		Projects:  []ProjectSettings{{Project: "old"}, {Project: "work:api"}},
+	}
+	cfg.NormalizeRoots()
+
+	if err := cfg.RemoveRoot("dev"); err != nil {
+		t.Fatalf("RemoveRoot: %v", err)
+	}
+
+	wantFavs := []string{"api", outside}
+	if len(cfg.Favorites) != len(wantFavs) || cfg.Favorites[0] != wantFavs[0] || cfg.Favorites[1] != wantFavs[1] {
+		t.Errorf("favorites = %v, want %v", cfg.Favorites, wantFavs)
+	}
+	for _, mons := range [][]MonitorConfig{cfg.Monitors, cfg.Profiles[0].Monitors} {
+		var got []string
+		for _, w := range mons[0].Windows {
+			got = append(got, w.Project)
+		}
+		if want := []string{"", "api", "api", ""}; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("window projects = %q, want %q", got, want)
+		}
+	}
+	if got := cfg.Projects[0].Project; got != filepath.Join(dev, "old") {
+		t.Errorf("settings in the removed root should keep their path, got %q", got)
+	}
+	if got := cfg.Projects[1].Project; got != "api" {
+		t.Errorf("settings project = %q, want api", got)
+	}
+}
+
+func TestResolveProject(t *testing.T) {
+	work := t.TempDir()
+	src := t.TempDir()
+	for _, d := range []string{
+		filepath.Join(work, "api"),
+		filepath.Join(src, "api"),
+		filepath.Join(src, "forks", "cli"),
+	} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &Config{
+		Roots:     []ProjectRoot{{Name: "work", Path: work}, {Name: "src", Path: src}},
+		Favorites: []string{"src:forks/cli"},
+	}
+	cfg.NormalizeRoots()
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{ref: "api", want: filepath.Join(work, "api")},
+		{ref: "src:api", want: filepath.Join(src, "api")},
+		{ref: "SRC:forks/cli", want: filepath.Join(src, "forks", "cli")},
+		{ref: "forks/cli", want: filepath.Join(src, "forks", "cli")},
+		{ref: "cli", want: filepath.Join(src, "forks", "cli")},
+		{ref: "src:nope", wantErr: ErrProjectNotFound},
+		{ref: "nope", wantErr: ErrProjectNotFound},
+	}
+	for _, tt := range tests {
+		got, err := cfg.ResolveProject(tt.ref)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: expected %v, got %q, %v", tt.ref, tt.wantErr, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	if ref := cfg.ProjectRef(filepath.Join(src, "forks", "cli")); ref != "src:forks/cli" {
+		t.Errorf("ProjectRef in secondary root = %q", ref)
+	}
+	if ref := cfg.ProjectRef(filepath.Join(work, "api")); ref != "api" {
+		t.Errorf("ProjectRef in primary root = %q", ref)
+	}
+}
+
+func TestResolveProjectAmbiguousFavorite(t *testing.T) {
+	work := t.TempDir()
+	src := t.TempDir()
+	for _, d := range []string{
+		filepath.Join(work, "api", "cli"),
+		filepath.Join(src, "forks", "cli"),
+	} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &Config{
+		Roots:     []ProjectRoot{{Name: "work", Path: work}, {Name: "src", Path: src}},
+		Favorites: []string{"src:forks/cli", "work:api/cli"},
+	}
+	cfg.NormalizeRoots()
+
+	if got, err := cfg.ResolveProject("cli"); !errors.Is(err, ErrAmbiguousProject) {
+		t.Errorf("cli: expected %v, got %q, %v", ErrAmbiguousProject, got, err)
+	}
+	// Longer paths still pick one
+	if got, err := cfg.ResolveProject("forks/cli"); err != nil || got != filepath.Join(src, "forks", "cli") {
+		t.Errorf("forks/cli: got %q, %v", got, err)
+	}
+}
+
+func TestDefaultRootName(t *testing.T) {
+	tests := map[string]string{
+		"/home/me/.1dev":     "1dev",
+		"/home/me/work":      "work",
+		"/home/me/my stuff/": "my-stuff",
+		"/":                  "projects",
+	}
+	for path, want := range tests {
+		if got := DefaultRootName(path); got != want {
+			t.Errorf("DefaultRootName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
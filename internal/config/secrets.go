@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SecretTimeout bounds how long resolving one secret reference may take.
+var SecretTimeout = 10 * time.Second
+
+// secretWaitDelay is how long a timed-out command's output is waited for
+// after it is killed, as processes it started may still hold it open.
+const secretWaitDelay = time.Second
+
+// DefaultSecretHelpers are the helpers for scheme:// references that
+// work without configuration. secretHelpers in the config adds to and
+// overrides them.
+var DefaultSecretHelpers = map[string]string{
+	"op": "op read {ref}",
+}
+
+// secretHelpers returns the helper command of each reference scheme. cfg
+// may be nil.
+func secretHelpers(cfg *Config) map[string]string {
+	helpers := make(map[string]string, len(DefaultSecretHelpers))
+	for scheme, command := range DefaultSecretHelpers {
+		helpers[scheme] = command
+	}
+	if cfg != nil {
+		for scheme, command := range cfg.SecretHelpers {
+			helpers[scheme] = command
+		}
+	}
+	return helpers
+}
+
+// helperScheme returns the scheme of a SCHEME:// reference with a helper.
+func helperScheme(cfg *Config, value string) (string, bool) {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return "", false
+	}
+	_, ok = secretHelpers(cfg)[scheme]
+	return scheme, ok
+}
+
+// IsSecretRef reports whether a keys file value is a secret reference
+// rather than the secret itself. References are resolved when a tool
+// launches and have one of these forms:
+//
+//	env:NAME        the NAME environment variable
+//	file:PATH       the contents of PATH, trimmed; ~ is expanded
+//	cmd:COMMAND     the output of COMMAND run by the shell, trimmed
+//	SCHEME://...    the output of the helper configured for SCHEME, e.g.
+//	                op://vault/item/field runs `op read op://vault/item/field`
+//
+// Anything else, including URLs without a helper, is a literal value.
+func IsSecretRef(cfg *Config, value string) bool {
+	for _, prefix := range []string{"env:", "file:", "cmd:"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	_, ok := helperScheme(cfg, value)
+	return ok
+}
+
+// DisplayKeyValue returns how a keys file value is shown: references as
+// written, since they hold no secret, and literal values masked.
+func DisplayKeyValue(cfg *Config, value string) string {
+	if IsSecretRef(cfg, value) {
+		return value
+	}
+	return MaskValue(value)
+}
+
+// ResolveSecret returns the value a keys file value stands for: the
+// referenced secret for a reference, otherwise the value itself. Errors
+// name the reference, never a secret.
+func ResolveSecret(cfg *Config, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("%s: %s is not set", value, name)
+		}
+		return v, nil
+	case strings.HasPrefix(value, "file:"):
+		data, err := os.ReadFile(expandHome(strings.TrimPrefix(value, "file:")))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", value, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(value, "cmd:"):
+		command := strings.TrimPrefix(value, "cmd:")
+		if runtime.GOOS == "windows" {
+			return runSecretCommand(value, "cmd", "/C", command)
+		}
+		return runSecretCommand(value, "sh", "-c", command)
+	}
+
+	scheme, ok := helperScheme(cfg, value)
+	if !ok {
+		return value, nil
+	}
+	parts := strings.Fields(secretHelpers(cfg)[scheme])
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%s: the %s helper is empty", value, scheme)
+	}
+	substituted := false
+	for i, p := range parts {
+		if strings.Contains(p, "{ref}") {
+			parts[i] = strings.ReplaceAll(p, "{ref}", value)
+			substituted = true
+		}
+	}
+	if !substituted {
+		parts = append(parts, value)
+	}
+	return runSecretCommand(value, parts[0], parts[1:]...)
+}
+
+// runSecretCommand runs a command resolving ref and returns its trimmed
+// output.
+func runSecretCommand(ref, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SecretTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	c.WaitDelay = secretWaitDelay
+	err := c.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s: timed out after %s", ref, SecretTimeout)
+	}
+	if err != nil {
+		if msg, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", ref, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+	value := strings.TrimSpace(stdout.String())
+	if value == "" {
+		return "", fmt.Errorf("%s: printed nothing", ref)
+	}
+	return value, nil
+}
+
+// ResolveKeys returns an account's env vars with every secret reference
+// resolved, for launching one of its commands.
+func ResolveKeys(cfg *Config, keys AccountKeys, accountID string) (map[string]string, error) {
+	ak := KeysForAccount(keys, accountID)
+	if len(ak) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(ak))
+	for name := range ak {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make(map[string]string, len(ak))
+	for _, name := range names {
+		value, err := ResolveSecret(cfg, ak[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s for %s: %w", name, accountID, err)
+		}
+		env[name] = value
+	}
+	return env, nil
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsSecretRef(t *testing.T) {
+	cfg := &Config{SecretHelpers: map[string]string{"vault": "vault kv get -field=value {ref}"}}
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"env:OPENAI_API_KEY", true},
+		{"file:~/.secrets/openai", true},
+		{"cmd:pass show openai/api", true},
+		{"op://vault/item/field", true},
+		{"vault://secret/openai", true},
+		{"sk-literal-123", false},
+		{"https://api.example.com/v1", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsSecretRef(cfg, tt.value); got != tt.want {
+			t.Errorf("IsSecretRef(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if IsSecretRef(nil, "vault://secret/openai") {
+		t.Error("vault:// is a reference without a configured helper")
+	}
+}
+
+func TestDisplayKeyValue(t *testing.T) {
+	if got := DisplayKeyValue(nil, "env:OPENAI_API_KEY"); got != "env:OPENAI_API_KEY" {
+		t.Errorf("reference displayed as %q", got)
+	}
+	if got := DisplayKeyValue(nil, "sk-literal-123"); strings.Contains(got, "literal") {
+		t.Errorf("literal displayed as %q", got)
+	}
+}
+
+func TestResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir)
+	t.Setenv("QS_TEST_SECRET", "from-env")
+	if err := os.WriteFile(filepath.Join(dir, "secret"), []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"sk-literal", "sk-literal"},
+		{"env:QS_TEST_SECRET", "from-env"},
+		{"file:~/secret", "from-file"},
+		{"cmd:echo from-cmd", "from-cmd"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveSecret(nil, tt.value)
+		if err != nil {
+			t.Errorf("ResolveSecret(%q): %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveSecret(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"env:QS_TEST_UNSET", "file:~/missing", "cmd:exit 3"} {
+		_, err := ResolveSecret(nil, value)
+		if err == nil {
+			t.Errorf("ResolveSecret(%q) succeeded", value)
+			continue
+		}
+		if !strings.Contains(err.Error(), value) {
+			t.Errorf("error %q doesn't name the reference %q", err, value)
+		}
+	}
+}
+
+func TestResolveSecretHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses echo as the helper")
+	}
+	cfg := &Config{SecretHelpers: map[string]string{"test": "echo resolved {ref}"}}
+	got, err := ResolveSecret(cfg, "test://vault/item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "resolved test://vault/item" {
+		t.Errorf("ResolveSecret = %q", got)
+	}
+}
+
+func TestResolveSecretTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	timeout := SecretTimeout
+	SecretTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { SecretTimeout = timeout })
+
+	// sleep outlives the killed shell and keeps its output open
+	start := time.Now()
+	_, err := ResolveSecret(nil, "cmd:sleep 30 | cat")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("ResolveSecret = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ResolveSecret took %s after timing out", elapsed)
+	}
+}
+
+func TestResolveKeys(t *testing.T) {
+	t.Setenv("QS_TEST_SECRET", "from-env")
+	keys := AccountKeys{"codex": {
+		"OPENAI_API_KEY":  "env:QS_TEST_SECRET",
+		"OPENAI_BASE_URL": "https://api.example.com/v1",
+	}}
+	env, err := ResolveKeys(nil, keys, "codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env["OPENAI_API_KEY"] != "from-env" || env["OPENAI_BASE_URL"] != "https://api.example.com/v1" {
+		t.Errorf("ResolveKeys = %v", env)
+	}
+	// The stored reference is left alone
+	if keys["codex"]["OPENAI_API_KEY"] != "env:QS_TEST_SECRET" {
+		t.Error("ResolveKeys modified the keys")
+	}
+
+	keys["codex"]["OTHER_KEY"] = "env:QS_TEST_UNSET"
+	if _, err := ResolveKeys(nil, keys, "codex"); err == nil || !strings.Contains(err.Error(), "OTHER_KEY") {
+		t.Errorf("err = %v, want one naming OTHER_KEY", err)
+	}
+}
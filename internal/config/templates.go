@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template scaffolds a new project from the picker's create-new-folder row.
+// The project starts as a copy of Path or a clone of Git (at most one of
+// them), then gets Files written into it and Commands run in it. Paths,
+// file names, file contents and commands may use {{name}}-style variables.
+type Template struct {
+	Name     string            `yaml:"name"`
+	Path     string            `yaml:"path,omitempty"`     // local directory copied into the project
+	Git      string            `yaml:"git,omitempty"`      // repository URL or path cloned into the project, without its history
+	Files    map[string]string `yaml:"files,omitempty"`    // files written after copying, e.g. CLAUDE.md or AGENTS.md
+	Commands []string          `yaml:"commands,omitempty"` // shell commands run in the project, e.g. "go mod init {{name}}"
+	Vars     map[string]string `yaml:"vars,omitempty"`     // extra variables, on top of the built-in ones
+}
+
+// Validate checks that a template has a name and at most one source.
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if t.Path != "" && t.Git != "" {
+		return fmt.Errorf("template %q sets both path and git: use one", t.Name)
+	}
+	return nil
+}
+
+// TemplateByName returns the template with the given name (case-insensitive), or nil.
+func (c *Config) TemplateByName(name string) *Template {
+	for i := range c.Templates {
+		if strings.EqualFold(c.Templates[i].Name, name) {
+			return &c.Templates[i]
+		}
+	}
+	return nil
+}
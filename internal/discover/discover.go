@@ -0,0 +1,334 @@
+// Package discover finds projects below the project roots: every directory
+// containing a marker such as .git, down to a depth limit. Results are kept
+// in an on-disk index that is refreshed incrementally, so a picker can show
+// the last scan immediately and update it in the background.
+package discover
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDepth is how many levels below a root are searched by default.
+const DefaultDepth = 3
+
+// IgnoreFile lists directories to skip, one pattern per line. It applies to
+// the directory it's in and everything below it.
+const IgnoreFile = ".qsignore"
+
+// indexVersion is bumped when the index format changes; older indexes are
+// discarded rather than migrated.
+const indexVersion = 1
+
+// DefaultMarkers are the entries that make a directory a project.
+var DefaultMarkers = []string{".git"}
+
+// Root is a named directory to search.
+type Root struct {
+	Name string
+	Path string
+}
+
+// Options controls a scan. Zero values use the defaults.
+type Options struct {
+	Depth   int
+	Markers []string
+}
+
+func (o Options) withDefaults() Options {
+	if o.Depth <= 0 {
+		o.Depth = DefaultDepth
+	}
+	if len(o.Markers) == 0 {
+		o.Markers = DefaultMarkers
+	}
+	return o
+}
+
+// Project is a discovered project directory.
+type Project struct {
+	Root string // name of the root it was found under
+	Path string // absolute path
+	Rel  string // slash-separated path relative to the root, e.g. "org/api"
+}
+
+// dirState caches what a directory contained at its last modification
+// time. Adding or removing an entry changes a directory's mtime, so an
+// unchanged mtime means the cached state still holds and the directory
+// doesn't need to be read again.
+type dirState struct {
+	ModTime time.Time `json:"mtime"`
+	Project bool      `json:"project,omitempty"`
+	Ignore  bool      `json:"ignore,omitempty"` // contains a .qsignore
+	Dirs    []string  `json:"dirs,omitempty"`   // subdirectories, without hidden ones
+}
+
+// Index is the cached result of the last scan, kept in ~/.qs/index.json.
+type Index struct {
+	Version  int                 `json:"version"`
+	Markers  []string            `json:"markers"`
+	Updated  time.Time           `json:"updated"`
+	Projects map[string][]string `json:"projects"` // root path -> project paths relative to it
+	Dirs     map[string]dirState `json:"dirs"`     // keyed by absolute path
+}
+
+// IndexPath returns the path to the index file (~/.qs/index.json)
+func IndexPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".qs", "index.json")
+}
+
+// LoadIndex reads the index file. If path is empty, uses IndexPath().
+// Returns an empty index if the file doesn't exist or was written by an
+// older version of qs.
+func LoadIndex(path string) (*Index, error) {
+	if path == "" {
+		path = IndexPath()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Index{}, nil
+		}
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if idx.Version != indexVersion {
+		return &Index{}, nil
+	}
+	return &idx, nil
+}
+
+// SaveIndex writes the index file. If path is empty, uses IndexPath(). The
+// file is replaced atomically so a picker never reads a partial index.
+func SaveIndex(idx *Index, path string) error {
+	if path == "" {
+		path = IndexPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	// Each writer has a temp file of its own, so pickers saving at the same
+	// time can't mix their writes; the last rename wins whole
+	f, err := os.CreateTemp(filepath.Dir(path), "index-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// Cached returns the projects found under roots by the last scan, and
+// whether every root has been scanned before.
+func (idx *Index) Cached(roots []Root) ([]Project, bool) {
+	complete := true
+	var projects []Project
+	for _, r := range roots {
+		rels, ok := idx.Projects[filepath.Clean(r.Path)]
+		if !ok {
+			complete = false
+			continue
+		}
+		for _, rel := range rels {
+			projects = append(projects, Project{
+				Root: r.Name,
+				Path: filepath.Join(r.Path, filepath.FromSlash(rel)),
+				Rel:  rel,
+			})
+		}
+	}
+	return projects, complete
+}
+
+// Refresh rescans roots and updates the index. Directories whose mtime is
+// unchanged since the last scan are not read again. Roots that are no
+// longer passed in are dropped from the index.
+func (idx *Index) Refresh(roots []Root, opts Options) []Project {
+	opts = opts.withDefaults()
+	if idx.Version != indexVersion || !equalStrings(idx.Markers, opts.Markers) {
+		// Cached project flags depend on the markers
+		idx.Dirs = nil
+	}
+
+	s := scanner{
+		opts: opts,
+		prev: idx.Dirs,
+		next: make(map[string]dirState),
+	}
+	projects := make(map[string][]string)
+	for _, r := range roots {
+		root := filepath.Clean(r.Path)
+		if _, done := projects[root]; done {
+			continue
+		}
+		var rels []string
+		s.walk(root, "", 0, nil, &rels)
+		sort.Strings(rels)
+		projects[root] = rels
+	}
+
+	idx.Version = indexVersion
+	idx.Markers = append([]string(nil), opts.Markers...)
+	idx.Updated = time.Now()
+	idx.Projects = projects
+	idx.Dirs = s.next
+
+	found, _ := idx.Cached(roots)
+	return found
+}
+
+type scanner struct {
+	opts Options
+	prev map[string]dirState
+	next map[string]dirState
+}
+
+// walk records dir's state and descends into its subdirectories. Projects
+// end the descent: repositories nested inside a project are part of it.
+func (s *scanner) walk(dir, rel string, depth int, rules []ignoreRule, out *[]string) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return
+	}
+
+	st, ok := s.prev[dir]
+	if !ok || !st.ModTime.Equal(info.ModTime()) {
+		st = readDir(dir, info.ModTime(), s.opts.Markers)
+	}
+	s.next[dir] = st
+
+	if depth > 0 && st.Project {
+		*out = append(*out, rel)
+		return
+	}
+	if depth >= s.opts.Depth {
+		return
+	}
+
+	if st.Ignore {
+		rules = append(rules[:len(rules):len(rules)], loadIgnore(dir)...)
+	}
+	for _, name := range st.Dirs {
+		child := filepath.Join(dir, name)
+		if ignored(rules, child) {
+			continue
+		}
+		s.walk(child, path.Join(rel, name), depth+1, rules, out)
+	}
+}
+
+// readDir reads a directory's markers, ignore file and subdirectories.
+// Hidden directories and symlinks are not descended into.
+func readDir(dir string, modTime time.Time, markers []string) dirState {
+	st := dirState{ModTime: modTime}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return st
+	}
+	for _, e := range entries {
+		name := e.Name()
+		for _, m := range markers {
+			if name == m {
+				st.Project = true
+			}
+		}
+		if name == IgnoreFile {
+			st.Ignore = true
+		}
+		if e.IsDir() && !strings.HasPrefix(name, ".") {
+			st.Dirs = append(st.Dirs, name)
+		}
+	}
+	return st
+}
+
+// ignoreRule is one .qsignore pattern. Patterns containing a slash match the
+// path relative to the ignore file's directory; others match any directory
+// name below it.
+type ignoreRule struct {
+	base    string
+	pattern string
+}
+
+func loadIgnore(dir string) []ignoreRule {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.Trim(filepath.ToSlash(line), "/")
+		if line == "" {
+			continue
+		}
+		rules = append(rules, ignoreRule{base: dir, pattern: line})
+	}
+	return rules
+}
+
+func ignored(rules []ignoreRule, dir string) bool {
+	for _, r := range rules {
+		if strings.Contains(r.pattern, "/") {
+			rel, err := filepath.Rel(r.base, dir)
+			if err != nil {
+				continue
+			}
+			if ok, _ := path.Match(r.pattern, filepath.ToSlash(rel)); ok {
+				return true
+			}
+		} else if ok, _ := path.Match(r.pattern, filepath.Base(dir)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
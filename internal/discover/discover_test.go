@@ -0,0 +1,174 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mkRepo creates root/rel with a .git directory inside.
+func mkRepo(t *testing.T, root, rel string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(rel), ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rels(projects []Project) string {
+	var out []string
+	for _, p := range projects {
+		out = append(out, p.Root+":"+p.Rel)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestRefreshFindsReposToDepth(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	mkRepo(t, root, "org/web")
+	mkRepo(t, root, "org/web/vendor/lib") // inside a project: not listed
+	mkRepo(t, root, "a/b/c/too-deep")
+	os.MkdirAll(filepath.Join(root, "plain"), 0755)
+	os.MkdirAll(filepath.Join(root, ".hidden", "repo", ".git"), 0755)
+
+	idx := &Index{}
+	got := idx.Refresh([]Root{{Name: "dev", Path: root}}, Options{})
+	if want := "dev:api,dev:org/web"; rels(got) != want {
+		t.Errorf("got %s, want %s", rels(got), want)
+	}
+	if got[1].Path != filepath.Join(root, "org", "web") {
+		t.Errorf("unexpected path %s", got[1].Path)
+	}
+
+	got = idx.Refresh([]Root{{Name: "dev", Path: root}}, Options{Depth: 4})
+	if want := "dev:a/b/c/too-deep,dev:api,dev:org/web"; rels(got) != want {
+		t.Errorf("depth 4: got %s, want %s", rels(got), want)
+	}
+}
+
+func TestRefreshMarkers(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	os.MkdirAll(filepath.Join(root, "tool"), 0755)
+	os.WriteFile(filepath.Join(root, "tool", "go.mod"), []byte("module tool\n"), 0644)
+
+	idx := &Index{}
+	roots := []Root{{Name: "dev", Path: root}}
+	if got := rels(idx.Refresh(roots, Options{})); got != "dev:api" {
+		t.Errorf("default markers: got %s", got)
+	}
+	// Changing the markers invalidates the cached project flags
+	if got := rels(idx.Refresh(roots, Options{Markers: []string{".git", "go.mod"}})); got != "dev:api,dev:tool" {
+		t.Errorf("with go.mod marker: got %s", got)
+	}
+}
+
+func TestRefreshHonorsIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	mkRepo(t, root, "archive/old")
+	mkRepo(t, root, "org/node_modules/dep")
+	mkRepo(t, root, "org/keep")
+	mkRepo(t, root, "org/skip")
+	os.WriteFile(filepath.Join(root, IgnoreFile), []byte("# comment\narchive/\nnode_modules\n"), 0644)
+	os.WriteFile(filepath.Join(root, "org", IgnoreFile), []byte("/skip\n"), 0644)
+
+	got := (&Index{}).Refresh([]Root{{Name: "dev", Path: root}}, Options{})
+	if want := "dev:api,dev:org/keep"; rels(got) != want {
+		t.Errorf("got %s, want %s", rels(got), want)
+	}
+}
+
+func TestRefreshIsIncremental(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	os.MkdirAll(filepath.Join(root, "org"), 0755)
+	roots := []Root{{Name: "dev", Path: root}}
+
+	idx := &Index{}
+	idx.Refresh(roots, Options{})
+
+	// An unchanged directory is not read again: a cached state claiming
+	// org is a project is trusted while its mtime matches
+	org := filepath.Join(root, "org")
+	st := idx.Dirs[org]
+	st.Project = true
+	idx.Dirs[org] = st
+	if got := rels(idx.Refresh(roots, Options{})); got != "dev:api,dev:org" {
+		t.Fatalf("expected cached state reused, got %s", got)
+	}
+
+	// Adding and removing repos changes mtimes, so both are picked up
+	mkRepo(t, root, "new")
+	os.RemoveAll(filepath.Join(root, "api"))
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(root, later, later)
+	os.Chtimes(org, later, later)
+	if got := rels(idx.Refresh(roots, Options{})); got != "dev:new" {
+		t.Errorf("expected changes picked up, got %s", got)
+	}
+	if _, ok := idx.Dirs[filepath.Join(root, "api")]; ok {
+		t.Error("expected removed directory dropped from the index")
+	}
+}
+
+func TestIndexRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	idx, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadIndex on missing file: %v", err)
+	}
+	roots := []Root{{Name: "dev", Path: root}}
+	if _, complete := idx.Cached(roots); complete {
+		t.Error("expected empty index to be incomplete")
+	}
+
+	idx.Refresh(roots, Options{})
+	if err := SaveIndex(idx, path); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+	loaded, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+
+	// The cached projects take the root's current name
+	projects, complete := loaded.Cached([]Root{{Name: "src", Path: root}})
+	if !complete || rels(projects) != "src:api" {
+		t.Errorf("unexpected cached projects %s (complete=%v)", rels(projects), complete)
+	}
+}
+
+func TestSaveIndexConcurrent(t *testing.T) {
+	root := t.TempDir()
+	mkRepo(t, root, "api")
+	path := filepath.Join(t.TempDir(), "index.json")
+	idx := &Index{}
+	idx.Refresh([]Root{{Name: "dev", Path: root}}, Options{})
+
+	// Pickers refreshing at once each replace the whole file
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := SaveIndex(idx, path); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := LoadIndex(path); err != nil {
+		t.Errorf("LoadIndex after concurrent saves: %v", err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp")); len(left) > 0 {
+		t.Errorf("temp files left behind: %v", left)
+	}
+}
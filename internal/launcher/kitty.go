@@ -0,0 +1,29 @@
+package launcher
+
+import "os/exec"
+
+// kittyTerminal spawns kitty OS windows. Windows are tagged with a WM_CLASS
+// equal to their title so they can be found again with xdotool.
+type kittyTerminal struct{}
+
+func (kittyTerminal) Name() string { return "kitty" }
+
+// Spawn runs: kitty --title <title> --class <title> --directory <workingDir> <command> <args...>
+func (kittyTerminal) Spawn(cfg LaunchConfig) error {
+	cmd := exec.Command("kitty", kittySpawnArgs(cfg)...)
+	cmd.Env = cfg.Env
+	detach(cmd)
+	return cmd.Start()
+}
+
+func kittySpawnArgs(cfg LaunchConfig) []string {
+	args := []string{"--title", cfg.Title, "--class", cfg.Title, "--directory", cfg.WorkingDir}
+	args = append(args, cfg.Command)
+	return append(args, cfg.Args...)
+}
+
+func (kittyTerminal) Locate(title string) (Window, error) { return x11LocateByClass(title) }
+
+func (kittyTerminal) Position(w Window, pos Position) error { return x11Position(w, pos) }
+
+func (kittyTerminal) Current() (Window, error) { return x11Current() }
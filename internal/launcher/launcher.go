@@ -1,89 +1,14 @@
 package launcher
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"strings"
 	"sync"
-	"syscall"
 	"time"
-	"unsafe"
 
 	"github.com/bcmister/qs/internal/monitor"
 )
 
-var (
-	user32             = syscall.NewLazyDLL("user32.dll")
-	procFindWindowW    = user32.NewProc("FindWindowW")
-	procSetWindowPos   = user32.NewProc("SetWindowPos")
-	procEnumWindows    = user32.NewProc("EnumWindows")
-	procGetWindowTextW = user32.NewProc("GetWindowTextW")
-	procGetWindowRect  = user32.NewProc("GetWindowRect")
-	procGetSystemMetrics = user32.NewProc("GetSystemMetrics")
-
-	kernel32             = syscall.NewLazyDLL("kernel32.dll")
-	procGetConsoleWindow = kernel32.NewProc("GetConsoleWindow")
-
-	dwmapi                    = syscall.NewLazyDLL("dwmapi.dll")
-	procDwmGetWindowAttribute = dwmapi.NewProc("DwmGetWindowAttribute")
-)
-
-const (
-	SWP_NOZORDER   = 0x0004
-	SWP_SHOWWINDOW = 0x0040
-	HWND_TOP       = 0
-
-	SM_CXSIZEFRAME             = 32
-	SM_CXPADDEDBORDER          = 92
-	DWMWA_EXTENDED_FRAME_BOUNDS = 9
-)
-
-// winRect matches the Win32 RECT structure (used for border measurement).
-type winRect struct {
-	Left, Top, Right, Bottom int32
-}
-
-var (
-	borderOnce   sync.Once
-	cachedBorder int
-)
-
-// GetInvisibleBorderWidth returns the per-side invisible border width of
-// Windows 10/11 windows. Measured via DWM on the current console window
-// (comparing GetWindowRect with DWMWA_EXTENDED_FRAME_BOUNDS), with a
-// GetSystemMetrics fallback.
-func GetInvisibleBorderWidth() int {
-	borderOnce.Do(func() {
-		// Try DWM measurement on current console window
-		hwnd := GetCurrentConsoleWindow()
-		if hwnd != 0 {
-			var wr, dwmBounds winRect
-			ret, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&wr)))
-			if ret != 0 {
-				hr, _, _ := procDwmGetWindowAttribute.Call(
-					hwnd,
-					DWMWA_EXTENDED_FRAME_BOUNDS,
-					uintptr(unsafe.Pointer(&dwmBounds)),
-					uintptr(unsafe.Sizeof(dwmBounds)),
-				)
-				if hr == 0 { // S_OK
-					border := int(dwmBounds.Left - wr.Left)
-					if border > 0 {
-						cachedBorder = border
-						return
-					}
-				}
-			}
-		}
-		// Fallback: GetSystemMetrics
-		frame, _, _ := procGetSystemMetrics.Call(SM_CXSIZEFRAME)
-		padded, _, _ := procGetSystemMetrics.Call(SM_CXPADDEDBORDER)
-		cachedBorder = int(frame + padded)
-	})
-	return cachedBorder
-}
-
 // Position represents a window position and size
 type Position struct {
 	X      int
@@ -100,9 +25,14 @@ type LaunchConfig struct {
 	Y          int
 	Width      int
 	Height     int
-	Command    string            // executable name, e.g. "claude"
-	Args       []string          // arguments, e.g. ["--dangerously-skip-permissions"]
-	Env        map[string]string // extra env vars to inject (nil = inherit parent env as-is)
+	Command    string   // executable name, e.g. "claude"
+	Args       []string // arguments, e.g. ["--dangerously-skip-permissions"]
+	Env        []string // the tool's whole environment, from config.BuildEnv (nil = inherit ours as-is)
+}
+
+// position returns the target rectangle of the window described by cfg.
+func (cfg LaunchConfig) position() Position {
+	return Position{X: cfg.X, Y: cfg.Y, Width: cfg.Width, Height: cfg.Height}
 }
 
 // LaunchResult holds the outcome of a terminal launch
@@ -197,33 +127,19 @@ func calculateHorizontal(mon *monitor.Monitor, count int) []Position {
 	return positions
 }
 
-// LaunchTerminal launches a single terminal window using wt.exe.
-// The command runs: wt.exe --title <title> -d <workingDir> <command> <args...>
-func LaunchTerminal(cfg LaunchConfig) error {
-	args := []string{"--title", cfg.Title, "-d", cfg.WorkingDir}
-	args = append(args, cfg.Command)
-	args = append(args, cfg.Args...)
-
-	cmd := exec.Command("wt", args...)
-	applyEnv(cmd, cfg.Env)
-	return cmd.Start()
+// LaunchTerminal launches a single terminal window using the given backend.
+func LaunchTerminal(term Terminal, cfg LaunchConfig) error {
+	return term.Spawn(cfg)
 }
 
-// LaunchAll launches all terminals in parallel and positions them.
-func LaunchAll(configs []LaunchConfig) []LaunchResult {
+// LaunchAll launches all terminals and positions them in parallel.
+func LaunchAll(term Terminal, configs []LaunchConfig) []LaunchResult {
 	results := make([]LaunchResult, len(configs))
 
-	// Phase 1: Launch all wt processes
+	// Phase 1: Spawn all terminals
 	for i, cfg := range configs {
 		results[i].Title = cfg.Title
-
-		args := []string{"--title", cfg.Title, "-d", cfg.WorkingDir}
-		args = append(args, cfg.Command)
-		args = append(args, cfg.Args...)
-
-		cmd := exec.Command("wt", args...)
-		applyEnv(cmd, cfg.Env)
-		if err := cmd.Start(); err != nil {
+		if err := term.Spawn(cfg); err != nil {
 			results[i].Err = fmt.Errorf("failed to launch: %w", err)
 		}
 	}
@@ -239,14 +155,7 @@ func LaunchAll(configs []LaunchConfig) []LaunchResult {
 		wg.Add(1)
 		go func(idx int, c LaunchConfig) {
 			defer wg.Done()
-			hwnd, err := findWindowByTitle(c.Title)
-			if err != nil {
-				results[idx].Err = fmt.Errorf("failed to find window: %w", err)
-				return
-			}
-			if err := setWindowPosition(hwnd, c.X, c.Y, c.Width, c.Height); err != nil {
-				results[idx].Err = fmt.Errorf("failed to position: %w", err)
-			}
+			results[idx].Err = locateAndPosition(term, c)
 		}(i, cfg)
 	}
 	wg.Wait()
@@ -255,9 +164,9 @@ func LaunchAll(configs []LaunchConfig) []LaunchResult {
 }
 
 // LaunchAllWithCurrent launches terminals where index 0 uses the current terminal
-// and indexes 1+ spawn new windows. Each spawned window runs "wt.exe ... qs" so
+// and indexes 1+ spawn new windows. Each spawned window typically runs "qs" so
 // it gets its own picker TUI.
-func LaunchAllWithCurrent(configs []LaunchConfig) LaunchAllWithCurrentResult {
+func LaunchAllWithCurrent(term Terminal, configs []LaunchConfig) LaunchAllWithCurrentResult {
 	if len(configs) == 0 {
 		return LaunchAllWithCurrentResult{
 			Results: nil,
@@ -269,19 +178,13 @@ func LaunchAllWithCurrent(configs []LaunchConfig) LaunchAllWithCurrentResult {
 		results[i].Title = cfg.Title
 	}
 
-	// Get current console window handle for positioning
-	currentHwnd := GetCurrentConsoleWindow()
+	// Get current window for positioning (not every backend can find it)
+	current, currentErr := term.Current()
 
-	// Launch additional windows (configs[1:]) — each runs "qs" for its own picker
+	// Launch additional windows (configs[1:])
 	if len(configs) > 1 {
 		for i := 1; i < len(configs); i++ {
-			cfg := configs[i]
-			args := []string{"--title", cfg.Title, "-d", cfg.WorkingDir}
-			args = append(args, cfg.Command)
-			args = append(args, cfg.Args...)
-			cmd := exec.Command("wt", args...)
-			applyEnv(cmd, cfg.Env)
-			if err := cmd.Start(); err != nil {
+			if err := term.Spawn(configs[i]); err != nil {
 				results[i].Err = fmt.Errorf("failed to launch: %w", err)
 			}
 		}
@@ -294,12 +197,12 @@ func LaunchAllWithCurrent(configs []LaunchConfig) LaunchAllWithCurrentResult {
 	var wg sync.WaitGroup
 
 	// Position current terminal (index 0)
-	if currentHwnd != 0 {
+	if currentErr == nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			cfg := configs[0]
-			if err := setWindowPosition(currentHwnd, cfg.X, cfg.Y, cfg.Width, cfg.Height); err != nil {
+			err := term.Position(current, configs[0].position())
+			if err != nil && !errors.Is(err, ErrPositionUnsupported) {
 				results[0].Err = fmt.Errorf("failed to position current window: %w", err)
 			}
 		}()
@@ -313,14 +216,7 @@ func LaunchAllWithCurrent(configs []LaunchConfig) LaunchAllWithCurrentResult {
 		wg.Add(1)
 		go func(idx int, cfg LaunchConfig) {
 			defer wg.Done()
-			hwnd, err := findWindowByTitle(cfg.Title)
-			if err != nil {
-				results[idx].Err = fmt.Errorf("failed to find window: %w", err)
-				return
-			}
-			if err := setWindowPosition(hwnd, cfg.X, cfg.Y, cfg.Width, cfg.Height); err != nil {
-				results[idx].Err = fmt.Errorf("failed to position: %w", err)
-			}
+			results[idx].Err = locateAndPosition(term, cfg)
 		}(i, configs[i])
 	}
 	wg.Wait()
@@ -335,82 +231,19 @@ type LaunchAllWithCurrentResult struct {
 	Results []LaunchResult
 }
 
-// PositionCurrentWindow positions the current console window
-func PositionCurrentWindow(x, y, w, h int) error {
-	hwnd := GetCurrentConsoleWindow()
-	if hwnd == 0 {
-		return fmt.Errorf("could not get current console window")
+// locateAndPosition finds the spawned window for cfg and moves it into place.
+// Backends that cannot place windows are not treated as failures.
+func locateAndPosition(term Terminal, cfg LaunchConfig) error {
+	w, err := term.Locate(cfg.Title)
+	if errors.Is(err, ErrPositionUnsupported) {
+		return nil
 	}
-	return setWindowPosition(hwnd, x, y, w, h)
-}
-
-// GetCurrentConsoleWindow returns the HWND of the current console window
-func GetCurrentConsoleWindow() uintptr {
-	hwnd, _, _ := procGetConsoleWindow.Call()
-	return hwnd
-}
-
-func findWindowByTitle(title string) (uintptr, error) {
-	var foundHwnd uintptr
-
-	for attempts := 0; attempts < 40; attempts++ {
-		callback := syscall.NewCallback(func(hwnd uintptr, lParam uintptr) uintptr {
-			var windowTitle [256]uint16
-			procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&windowTitle[0])), 256)
-
-			text := syscall.UTF16ToString(windowTitle[:])
-			if strings.Contains(text, title) {
-				foundHwnd = hwnd
-				return 0
-			}
-			return 1
-		})
-
-		procEnumWindows.Call(callback, 0)
-
-		if foundHwnd != 0 {
-			return foundHwnd, nil
-		}
-
-		time.Sleep(50 * time.Millisecond)
-	}
-
-	return 0, fmt.Errorf("window with title '%s' not found", title)
-}
-
-// applyEnv sets extra environment variables on a command.
-// If env is nil or empty, the command inherits the parent environment as-is.
-func applyEnv(cmd *exec.Cmd, env map[string]string) {
-	if len(env) == 0 {
-		return
+	if err != nil {
+		return fmt.Errorf("failed to find window: %w", err)
 	}
-	cmd.Env = os.Environ()
-	for k, v := range env {
-		cmd.Env = append(cmd.Env, k+"="+v)
+	err = term.Position(w, cfg.position())
+	if err != nil && !errors.Is(err, ErrPositionUnsupported) {
+		return fmt.Errorf("failed to position: %w", err)
 	}
-}
-
-func setWindowPosition(hwnd uintptr, x, y, width, height int) error {
-	// Compensate for Windows 10/11 invisible borders so visible window
-	// content tiles seamlessly edge-to-edge.
-	bw := GetInvisibleBorderWidth()
-	x -= bw
-	width += 2 * bw
-	height += bw // bottom border only; top has none for WT title bar
-
-	ret, _, err := procSetWindowPos.Call(
-		hwnd,
-		HWND_TOP,
-		uintptr(x),
-		uintptr(y),
-		uintptr(width),
-		uintptr(height),
-		SWP_NOZORDER|SWP_SHOWWINDOW,
-	)
-
-	if ret == 0 {
-		return fmt.Errorf("SetWindowPos failed: %v", err)
-	}
-
 	return nil
 }
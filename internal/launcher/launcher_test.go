@@ -1,6 +1,7 @@
 package launcher
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/bcmister/qs/internal/monitor"
@@ -237,3 +238,113 @@ func TestVerticalEdgeSnappingAllCells(t *testing.T) {
 		t.Errorf("last cell right=%d, expected %d", last.X+last.Width, mon.X+mon.Width)
 	}
 }
+
+func TestNewTerminal(t *testing.T) {
+	for _, name := range []string{"tmux", "kitty", "wezterm", " Tmux "} {
+		term, err := New(name)
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", name, err)
+		}
+		if term.Name() != strings.ToLower(strings.TrimSpace(name)) {
+			t.Errorf("New(%q).Name() = %q", name, term.Name())
+		}
+	}
+
+	if _, err := New("konsole"); err == nil {
+		t.Error("expected error for unknown terminal")
+	}
+}
+
+func TestTmuxSpawnArgs(t *testing.T) {
+	cfg := LaunchConfig{
+		Title:      "qs-1-1",
+		WorkingDir: "/home/me/dev",
+		Command:    "qs",
+		Args:       []string{"--project", "api"},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		create bool
+		prefix []string
+	}{
+		{"inside tmux", "", false, []string{"new-window", "-d"}},
+		{"existing session", "qs", false, []string{"new-window", "-d", "-t", "=qs:"}},
+		{"new session", "qs", true, []string{"new-session", "-d", "-s", "qs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := append(append([]string{}, tt.prefix...),
+				"-n", "qs-1-1", "-c", "/home/me/dev",
+				"qs", "--project", "api")
+			got := tmuxSpawnArgs(cfg, tt.target, tt.create)
+			if strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("got  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTmuxEnvArgs(t *testing.T) {
+	environ := []string{"HOME=/home/me", "OPENAI_API_KEY=old", "AWS_SECRET_ACCESS_KEY=s", "ANTHROPIC_API_KEY=a"}
+	env := []string{"HOME=/home/me", "OPENAI_API_KEY=new", "B_KEY=2"}
+	want := "-e OPENAI_API_KEY=new -e B_KEY=2 env -u ANTHROPIC_API_KEY -u AWS_SECRET_ACCESS_KEY"
+	if got := strings.Join(tmuxEnvArgs(environ, env), " "); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if got := tmuxEnvArgs(environ, nil); got != nil {
+		t.Errorf("expected no flags for an inherited environment, got %q", got)
+	}
+}
+
+func TestFindTmuxWindow(t *testing.T) {
+	listing := "@1\tzsh\n@4\tqs-1-2\n@7\tqs-1-1\n"
+	if got := findTmuxWindow(listing, "qs-1-1"); got != "@7" {
+		t.Errorf("expected @7, got %q", got)
+	}
+	if got := findTmuxWindow(listing, "qs-2-1"); got != "" {
+		t.Errorf("expected no match, got %q", got)
+	}
+}
+
+func TestTmuxSessionCommands(t *testing.T) {
+	pane := LaunchConfig{WorkingDir: "/dev", Command: "qs"}
+	windows := []TmuxWindow{
+		{Name: "monitor-1", Layout: "vertical", Panes: []LaunchConfig{pane, pane}},
+		{Name: "monitor-2", Layout: "full", Panes: []LaunchConfig{pane}},
+	}
+
+	want := []string{
+		"new-session -d -s qs-all -n monitor-1 -c /dev qs",
+		"split-window -d -t =qs-all:monitor-1 -c /dev qs",
+		"select-layout -t =qs-all:monitor-1 tiled",
+		"select-layout -t =qs-all:monitor-1 even-horizontal",
+		"new-window -d -t =qs-all: -n monitor-2 -c /dev qs",
+	}
+
+	got := tmuxSessionCommands("qs-all", windows)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d commands, got %d: %q", len(want), len(got), got)
+	}
+	for i := range want {
+		if strings.Join(got[i], " ") != want[i] {
+			t.Errorf("command %d:\ngot  %q\nwant %q", i, strings.Join(got[i], " "), want[i])
+		}
+	}
+}
+
+func TestTmuxLayout(t *testing.T) {
+	tests := map[string]string{
+		"vertical":   "even-horizontal",
+		"horizontal": "even-vertical",
+		"grid":       "tiled",
+		"":           "tiled",
+	}
+	for layout, want := range tests {
+		if got := tmuxLayout(layout); got != want {
+			t.Errorf("tmuxLayout(%q) = %q, want %q", layout, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Window identifies a spawned terminal window within a backend. Its meaning
+// is backend-specific: an HWND for Windows Terminal, an X11 window id for
+// kitty and wezterm, or a tmux window id.
+type Window string
+
+// ErrPositionUnsupported is returned by backends that cannot locate or move
+// windows in the current session (tmux, or any X11 backend under Wayland).
+// LaunchAll treats it as success since the window was still spawned.
+var ErrPositionUnsupported = errors.New("window positioning is not supported by this terminal")
+
+// Terminal is a terminal emulator backend that can spawn, locate and
+// position windows.
+type Terminal interface {
+	// Name returns the config value that selects this backend.
+	Name() string
+	// Spawn starts a new window running cfg.Command in cfg.WorkingDir.
+	Spawn(cfg LaunchConfig) error
+	// Locate finds a previously spawned window by its title.
+	Locate(title string) (Window, error)
+	// Position moves and resizes a window.
+	Position(w Window, pos Position) error
+	// Current returns the window qs itself is running in.
+	Current() (Window, error)
+}
+
+// Names lists the accepted values for the config `terminal:` key.
+var Names = []string{"auto", "wt", "tmux", "kitty", "wezterm"}
+
+// New returns the terminal backend with the given name. An empty name or
+// "auto" picks a backend for the current platform and session.
+func New(name string) (Terminal, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return detectTerminal()
+	case "wt":
+		return newWindowsTerminal()
+	case "tmux":
+		return tmuxTerminal{}, nil
+	case "kitty":
+		return kittyTerminal{}, nil
+	case "wezterm":
+		return weztermTerminal{}, nil
+	default:
+		return nil, fmt.Errorf("unknown terminal %q (expected one of: %s)", name, strings.Join(Names, ", "))
+	}
+}
+
+// detectSessionTerminal picks a backend from the environment of the current
+// session: the terminal qs is already running inside wins, then the first
+// one found on PATH.
+func detectSessionTerminal() (Terminal, error) {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return tmuxTerminal{}, nil
+	case os.Getenv("KITTY_WINDOW_ID") != "":
+		return kittyTerminal{}, nil
+	case os.Getenv("WEZTERM_PANE") != "":
+		return weztermTerminal{}, nil
+	}
+
+	candidates := []Terminal{kittyTerminal{}, weztermTerminal{}, tmuxTerminal{}}
+	for _, t := range candidates {
+		if _, err := exec.LookPath(t.Name()); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("no supported terminal found — install tmux, kitty or wezterm, or set terminal: in ~/.qs/config.yaml")
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// detectTerminal picks a backend from the current session.
+func detectTerminal() (Terminal, error) {
+	return detectSessionTerminal()
+}
+
+// newWindowsTerminal is only available on Windows.
+func newWindowsTerminal() (Terminal, error) {
+	return nil, fmt.Errorf("terminal \"wt\" is only available on Windows, not %s", runtime.GOOS)
+}
+
+// GetInvisibleBorderWidth returns 0: only Windows 10/11 draw invisible
+// resize borders around windows.
+func GetInvisibleBorderWidth() int {
+	return 0
+}
+
+// detach starts cmd in its own session so spawned terminals outlive qs and
+// don't receive the SIGHUP sent when the launching terminal closes.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
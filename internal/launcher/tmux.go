@@ -0,0 +1,233 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// tmuxSession is the detached session windows are created in when qs is not
+// already running inside tmux.
+const tmuxSession = "qs"
+
+// tmuxExact makes a session target match only the session with exactly this
+// name; a bare name also matches by prefix, so "qs" would find "qs-all".
+func tmuxExact(session string) string { return "=" + session }
+
+// tmuxTerminal opens tmux windows instead of OS windows. tmux windows always
+// fill the client, so Position is unsupported.
+type tmuxTerminal struct{}
+
+func (tmuxTerminal) Name() string { return "tmux" }
+
+// Spawn opens a new window in the current tmux session, or in the detached
+// "qs" session (created on demand) when qs runs outside tmux.
+func (tmuxTerminal) Spawn(cfg LaunchConfig) error {
+	target := ""
+	create := false
+	if os.Getenv("TMUX") == "" {
+		target = tmuxSession
+		create = !TmuxSessionExists(tmuxSession)
+	}
+
+	out, err := exec.Command("tmux", tmuxSpawnArgs(cfg, target, create)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("tmux failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// tmuxSpawnArgs builds the tmux arguments for cfg. target names the session to
+// add the window to ("" = current session); create starts that session.
+// Env is passed with -e because panes are forked by the tmux server, not by us.
+func tmuxSpawnArgs(cfg LaunchConfig, target string, create bool) []string {
+	var args []string
+	switch {
+	case create:
+		args = []string{"new-session", "-d", "-s", target}
+	case target != "":
+		args = []string{"new-window", "-d", "-t", tmuxExact(target) + ":"}
+	default:
+		args = []string{"new-window", "-d"}
+	}
+	args = append(args, "-n", cfg.Title, "-c", cfg.WorkingDir)
+
+	return append(args, tmuxCommand(cfg)...)
+}
+
+func (tmuxTerminal) Locate(title string) (Window, error) {
+	out, err := exec.Command("tmux", "list-windows", "-a", "-F", "#{window_id}\t#{window_name}").Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux list-windows failed: %w", err)
+	}
+	if id := findTmuxWindow(string(out), title); id != "" {
+		return Window(id), nil
+	}
+	return "", fmt.Errorf("tmux window '%s' not found", title)
+}
+
+// findTmuxWindow returns the id of the window named title in
+// `tmux list-windows -F "#{window_id}\t#{window_name}"` output.
+func findTmuxWindow(listing, title string) string {
+	for _, line := range strings.Split(listing, "\n") {
+		id, name, ok := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+		if ok && name == title {
+			return id
+		}
+	}
+	return ""
+}
+
+func (tmuxTerminal) Position(w Window, pos Position) error {
+	return ErrPositionUnsupported
+}
+
+func (tmuxTerminal) Current() (Window, error) {
+	if os.Getenv("TMUX") == "" {
+		return "", fmt.Errorf("not running inside tmux")
+	}
+	out, err := exec.Command("tmux", "display-message", "-p", "#{window_id}").Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux display-message failed: %w", err)
+	}
+	return Window(strings.TrimSpace(string(out))), nil
+}
+
+// TmuxWindow describes one window of a session built by LaunchTmuxSession.
+// Each pane uses the Title, WorkingDir, Command, Args and Env of its
+// LaunchConfig; the position fields are ignored since tmux lays out panes.
+type TmuxWindow struct {
+	Name   string
+	Layout string // full, vertical, horizontal or grid
+	Panes  []LaunchConfig
+}
+
+// TmuxSessionExists reports whether a tmux session with the given name is running.
+func TmuxSessionExists(name string) bool {
+	return exec.Command("tmux", "has-session", "-t", tmuxExact(name)).Run() == nil
+}
+
+// LaunchTmuxSession creates a detached tmux session with one window per
+// entry in windows, split into panes following each window's layout.
+func LaunchTmuxSession(name string, windows []TmuxWindow) error {
+	for _, args := range tmuxSessionCommands(name, windows) {
+		out, err := exec.Command("tmux", args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("tmux %s failed: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// AttachTmuxSession attaches the terminal to the named session, or switches
+// the current client to it when qs is already running inside tmux.
+func AttachTmuxSession(name string) error {
+	args := []string{"attach-session", "-t", tmuxExact(name)}
+	if os.Getenv("TMUX") != "" {
+		args = []string{"switch-client", "-t", tmuxExact(name)}
+	}
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// tmuxSessionCommands returns the tmux invocations that build the session.
+// Panes are re-tiled after every split so tmux never runs out of room for
+// the next one; the final select-layout applies the requested layout.
+func tmuxSessionCommands(name string, windows []TmuxWindow) [][]string {
+	var cmds [][]string
+	for _, w := range windows {
+		if len(w.Panes) == 0 {
+			continue
+		}
+		target := tmuxExact(name) + ":" + w.Name
+
+		first := tmuxPaneArgs(w.Panes[0])
+		if len(cmds) == 0 {
+			cmds = append(cmds, append([]string{"new-session", "-d", "-s", name, "-n", w.Name}, first...))
+		} else {
+			cmds = append(cmds, append([]string{"new-window", "-d", "-t", tmuxExact(name) + ":", "-n", w.Name}, first...))
+		}
+
+		for _, pane := range w.Panes[1:] {
+			cmds = append(cmds, append([]string{"split-window", "-d", "-t", target}, tmuxPaneArgs(pane)...))
+			cmds = append(cmds, []string{"select-layout", "-t", target, "tiled"})
+		}
+		if len(w.Panes) > 1 {
+			cmds = append(cmds, []string{"select-layout", "-t", target, tmuxLayout(w.Layout)})
+		}
+	}
+	return cmds
+}
+
+// tmuxPaneArgs returns the working dir, env and command flags for a pane.
+func tmuxPaneArgs(cfg LaunchConfig) []string {
+	args := []string{"-c", cfg.WorkingDir}
+
+	return append(args, tmuxCommand(cfg)...)
+}
+
+// tmuxCommand returns the env flags for cfg followed by its command.
+func tmuxCommand(cfg LaunchConfig) []string {
+	args := tmuxEnvArgs(os.Environ(), cfg.Env)
+	args = append(args, cfg.Command)
+	return append(args, cfg.Args...)
+}
+
+// tmuxEnvArgs returns the flags that give a pane env instead of environ.
+// Panes inherit the tmux server's environment, not ours, so variables env
+// sets or changes are passed with -e and those it drops are removed by
+// running the command through env -u.
+func tmuxEnvArgs(environ, env []string) []string {
+	if env == nil {
+		return nil
+	}
+	inherited := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		inherited[name] = value
+	}
+
+	var args []string
+	kept := make(map[string]bool, len(env))
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		kept[name] = true
+		if old, ok := inherited[name]; !ok || old != value {
+			args = append(args, "-e", kv)
+		}
+	}
+	var unset []string
+	for name := range inherited {
+		if name != "" && !kept[name] {
+			unset = append(unset, name)
+		}
+	}
+	if len(unset) == 0 {
+		return args
+	}
+	sort.Strings(unset)
+	args = append(args, "env")
+	for _, name := range unset {
+		args = append(args, "-u", name)
+	}
+	return args
+}
+
+// tmuxLayout maps a qs layout name to the equivalent tmux preset. Note the
+// naming is inverted: qs "vertical" means side-by-side columns, which tmux
+// calls even-horizontal.
+func tmuxLayout(layout string) string {
+	switch layout {
+	case "vertical":
+		return "even-horizontal"
+	case "horizontal":
+		return "even-vertical"
+	default:
+		return "tiled"
+	}
+}
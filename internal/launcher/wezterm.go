@@ -0,0 +1,29 @@
+package launcher
+
+import "os/exec"
+
+// weztermTerminal spawns WezTerm GUI windows. Like kitty, windows are tagged
+// with a WM_CLASS equal to their title so they can be found with xdotool.
+type weztermTerminal struct{}
+
+func (weztermTerminal) Name() string { return "wezterm" }
+
+// Spawn runs: wezterm start --always-new-process --class <title> --cwd <workingDir> -- <command> <args...>
+func (weztermTerminal) Spawn(cfg LaunchConfig) error {
+	cmd := exec.Command("wezterm", weztermSpawnArgs(cfg)...)
+	cmd.Env = cfg.Env
+	detach(cmd)
+	return cmd.Start()
+}
+
+func weztermSpawnArgs(cfg LaunchConfig) []string {
+	args := []string{"start", "--always-new-process", "--class", cfg.Title, "--cwd", cfg.WorkingDir, "--"}
+	args = append(args, cfg.Command)
+	return append(args, cfg.Args...)
+}
+
+func (weztermTerminal) Locate(title string) (Window, error) { return x11LocateByClass(title) }
+
+func (weztermTerminal) Position(w Window, pos Position) error { return x11Position(w, pos) }
+
+func (weztermTerminal) Current() (Window, error) { return x11Current() }
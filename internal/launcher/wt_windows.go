@@ -0,0 +1,210 @@
+//go:build windows
+
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	procSetWindowPos     = user32.NewProc("SetWindowPos")
+	procEnumWindows      = user32.NewProc("EnumWindows")
+	procGetWindowTextW   = user32.NewProc("GetWindowTextW")
+	procGetWindowRect    = user32.NewProc("GetWindowRect")
+	procGetSystemMetrics = user32.NewProc("GetSystemMetrics")
+
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleWindow = kernel32.NewProc("GetConsoleWindow")
+
+	dwmapi                    = syscall.NewLazyDLL("dwmapi.dll")
+	procDwmGetWindowAttribute = dwmapi.NewProc("DwmGetWindowAttribute")
+)
+
+const (
+	SWP_NOZORDER   = 0x0004
+	SWP_SHOWWINDOW = 0x0040
+	HWND_TOP       = 0
+
+	SM_CXSIZEFRAME              = 32
+	SM_CXPADDEDBORDER           = 92
+	DWMWA_EXTENDED_FRAME_BOUNDS = 9
+)
+
+// winRect matches the Win32 RECT structure (used for border measurement).
+type winRect struct {
+	Left, Top, Right, Bottom int32
+}
+
+var (
+	borderOnce   sync.Once
+	cachedBorder int
+)
+
+// detectTerminal returns Windows Terminal, the only backend that can place
+// native windows on Windows.
+func detectTerminal() (Terminal, error) {
+	return wtTerminal{}, nil
+}
+
+func newWindowsTerminal() (Terminal, error) {
+	return wtTerminal{}, nil
+}
+
+// detach is a no-op on Windows: wt.exe hands the window off to the
+// Windows Terminal host process and exits immediately.
+func detach(cmd *exec.Cmd) {}
+
+// wtTerminal spawns Windows Terminal windows and positions them with Win32.
+type wtTerminal struct{}
+
+func (wtTerminal) Name() string { return "wt" }
+
+// Spawn runs: wt.exe --title <title> -d <workingDir> <command> <args...>
+func (wtTerminal) Spawn(cfg LaunchConfig) error {
+	args := []string{"--title", cfg.Title, "-d", cfg.WorkingDir}
+	args = append(args, cfg.Command)
+	args = append(args, cfg.Args...)
+
+	cmd := exec.Command("wt", args...)
+	cmd.Env = cfg.Env
+	return cmd.Start()
+}
+
+func (wtTerminal) Locate(title string) (Window, error) {
+	hwnd, err := findWindowByTitle(title)
+	if err != nil {
+		return "", err
+	}
+	return hwndWindow(hwnd), nil
+}
+
+func (wtTerminal) Position(w Window, pos Position) error {
+	hwnd, err := strconv.ParseUint(string(w), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid window handle %q", w)
+	}
+	return setWindowPosition(uintptr(hwnd), pos.X, pos.Y, pos.Width, pos.Height)
+}
+
+func (wtTerminal) Current() (Window, error) {
+	hwnd := GetCurrentConsoleWindow()
+	if hwnd == 0 {
+		return "", fmt.Errorf("could not get current console window")
+	}
+	return hwndWindow(hwnd), nil
+}
+
+func hwndWindow(hwnd uintptr) Window {
+	return Window(strconv.FormatUint(uint64(hwnd), 10))
+}
+
+// GetInvisibleBorderWidth returns the per-side invisible border width of
+// Windows 10/11 windows. Measured via DWM on the current console window
+// (comparing GetWindowRect with DWMWA_EXTENDED_FRAME_BOUNDS), with a
+// GetSystemMetrics fallback.
+func GetInvisibleBorderWidth() int {
+	borderOnce.Do(func() {
+		// Try DWM measurement on current console window
+		hwnd := GetCurrentConsoleWindow()
+		if hwnd != 0 {
+			var wr, dwmBounds winRect
+			ret, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&wr)))
+			if ret != 0 {
+				hr, _, _ := procDwmGetWindowAttribute.Call(
+					hwnd,
+					DWMWA_EXTENDED_FRAME_BOUNDS,
+					uintptr(unsafe.Pointer(&dwmBounds)),
+					uintptr(unsafe.Sizeof(dwmBounds)),
+				)
+				if hr == 0 { // S_OK
+					border := int(dwmBounds.Left - wr.Left)
+					if border > 0 {
+						cachedBorder = border
+						return
+					}
+				}
+			}
+		}
+		// Fallback: GetSystemMetrics
+		frame, _, _ := procGetSystemMetrics.Call(SM_CXSIZEFRAME)
+		padded, _, _ := procGetSystemMetrics.Call(SM_CXPADDEDBORDER)
+		cachedBorder = int(frame + padded)
+	})
+	return cachedBorder
+}
+
+// PositionCurrentWindow positions the current console window
+func PositionCurrentWindow(x, y, w, h int) error {
+	hwnd := GetCurrentConsoleWindow()
+	if hwnd == 0 {
+		return fmt.Errorf("could not get current console window")
+	}
+	return setWindowPosition(hwnd, x, y, w, h)
+}
+
+// GetCurrentConsoleWindow returns the HWND of the current console window
+func GetCurrentConsoleWindow() uintptr {
+	hwnd, _, _ := procGetConsoleWindow.Call()
+	return hwnd
+}
+
+func findWindowByTitle(title string) (uintptr, error) {
+	var foundHwnd uintptr
+
+	for attempts := 0; attempts < 40; attempts++ {
+		callback := syscall.NewCallback(func(hwnd uintptr, lParam uintptr) uintptr {
+			var windowTitle [256]uint16
+			procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&windowTitle[0])), 256)
+
+			text := syscall.UTF16ToString(windowTitle[:])
+			if strings.Contains(text, title) {
+				foundHwnd = hwnd
+				return 0
+			}
+			return 1
+		})
+
+		procEnumWindows.Call(callback, 0)
+
+		if foundHwnd != 0 {
+			return foundHwnd, nil
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return 0, fmt.Errorf("window with title '%s' not found", title)
+}
+
+func setWindowPosition(hwnd uintptr, x, y, width, height int) error {
+	// Compensate for Windows 10/11 invisible borders so visible window
+	// content tiles seamlessly edge-to-edge.
+	bw := GetInvisibleBorderWidth()
+	x -= bw
+	width += 2 * bw
+	height += bw // bottom border only; top has none for WT title bar
+
+	ret, _, err := procSetWindowPos.Call(
+		hwnd,
+		HWND_TOP,
+		uintptr(x),
+		uintptr(y),
+		uintptr(width),
+		uintptr(height),
+		SWP_NOZORDER|SWP_SHOWWINDOW,
+	)
+
+	if ret == 0 {
+		return fmt.Errorf("SetWindowPos failed: %v", err)
+	}
+
+	return nil
+}
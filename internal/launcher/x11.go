@@ -0,0 +1,76 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// x11Available reports whether windows can be found and moved with xdotool.
+// Wayland compositors don't let clients place windows, so positioning is
+// only attempted in a plain X11 session.
+func x11Available() bool {
+	if os.Getenv("DISPLAY") == "" || os.Getenv("WAYLAND_DISPLAY") != "" {
+		return false
+	}
+	_, err := exec.LookPath("xdotool")
+	return err == nil
+}
+
+// x11LocateByClass polls for a window whose WM_CLASS matches class exactly.
+func x11LocateByClass(class string) (Window, error) {
+	if !x11Available() {
+		return "", ErrPositionUnsupported
+	}
+
+	pattern := "^" + regexp.QuoteMeta(class) + "$"
+	for attempts := 0; attempts < 40; attempts++ {
+		out, err := exec.Command("xdotool", "search", "--class", pattern).Output()
+		if err == nil {
+			if ids := strings.Fields(string(out)); len(ids) > 0 {
+				return Window(ids[len(ids)-1]), nil
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return "", fmt.Errorf("window with class '%s' not found", class)
+}
+
+// x11Position moves and resizes an X11 window.
+func x11Position(w Window, pos Position) error {
+	if !x11Available() {
+		return ErrPositionUnsupported
+	}
+	out, err := exec.Command("xdotool", x11PositionArgs(w, pos)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xdotool failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func x11PositionArgs(w Window, pos Position) []string {
+	return []string{
+		"windowsize", string(w), fmt.Sprint(pos.Width), fmt.Sprint(pos.Height),
+		"windowmove", string(w), fmt.Sprint(pos.X), fmt.Sprint(pos.Y),
+	}
+}
+
+// x11Current returns the X11 window qs is running in. Most terminals export
+// WINDOWID; otherwise the focused window is the best guess.
+func x11Current() (Window, error) {
+	if !x11Available() {
+		return "", ErrPositionUnsupported
+	}
+	if id := os.Getenv("WINDOWID"); id != "" {
+		return Window(id), nil
+	}
+	out, err := exec.Command("xdotool", "getactivewindow").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not get active window: %w", err)
+	}
+	return Window(strings.TrimSpace(string(out))), nil
+}
@@ -1,10 +1,6 @@
 package monitor
 
-import (
-	"fmt"
-	"syscall"
-	"unsafe"
-)
+import "fmt"
 
 // Monitor represents a display monitor
 type Monitor struct {
@@ -16,77 +12,11 @@ type Monitor struct {
 	Primary bool
 }
 
-var (
-	user32                  = syscall.NewLazyDLL("user32.dll")
-	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
-	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
-)
-
-// RECT structure
-type rect struct {
-	Left, Top, Right, Bottom int32
-}
-
-// MONITORINFOEXW structure
-type monitorInfoExW struct {
-	CbSize    uint32
-	RcMonitor rect
-	RcWork    rect
-	DwFlags   uint32
-	SzDevice  [32]uint16
-}
-
-const (
-	MONITORINFOF_PRIMARY = 0x00000001
-)
-
 // Detect returns a list of all connected monitors
 func Detect() ([]Monitor, error) {
-	var monitors []Monitor
-
-	// Callback function for EnumDisplayMonitors
-	callback := syscall.NewCallback(func(hMonitor uintptr, hdcMonitor uintptr, lprcMonitor uintptr, dwData uintptr) uintptr {
-		var info monitorInfoExW
-		info.CbSize = uint32(unsafe.Sizeof(info))
-
-		ret, _, _ := procGetMonitorInfoW.Call(
-			hMonitor,
-			uintptr(unsafe.Pointer(&info)),
-		)
-
-		if ret != 0 {
-			// Convert device name from UTF16 to string
-			deviceName := syscall.UTF16ToString(info.SzDevice[:])
-
-			m := Monitor{
-				Name:    deviceName,
-				X:       int(info.RcWork.Left),
-				Y:       int(info.RcWork.Top),
-				Width:   int(info.RcWork.Right - info.RcWork.Left),
-				Height:  int(info.RcWork.Bottom - info.RcWork.Top),
-				Primary: info.DwFlags&MONITORINFOF_PRIMARY != 0,
-			}
-
-			// Generate a friendly name if device name is technical
-			if m.Name == "" || m.Name[0] == '\\' {
-				m.Name = fmt.Sprintf("Display %d", len(monitors)+1)
-			}
-
-			monitors = append(monitors, m)
-		}
-
-		return 1 // Continue enumeration
-	})
-
-	ret, _, err := procEnumDisplayMonitors.Call(
-		0,        // hdc - NULL for all monitors
-		0,        // lprcClip - NULL for entire virtual screen
-		callback, // lpfnEnum
-		0,        // dwData
-	)
-
-	if ret == 0 {
-		return nil, fmt.Errorf("EnumDisplayMonitors failed: %v", err)
+	monitors, err := detect()
+	if err != nil {
+		return nil, err
 	}
 
 	// Sort monitors by X position (left to right)
@@ -110,6 +40,28 @@ func Detect() ([]Monitor, error) {
 	return monitors, nil
 }
 
+// ensurePrimary marks a primary monitor when the source has no notion of one
+// (Wayland compositors) or none is set: the monitor at the origin, else the
+// first one.
+func ensurePrimary(monitors []Monitor) []Monitor {
+	if len(monitors) == 0 {
+		return monitors
+	}
+	for _, m := range monitors {
+		if m.Primary {
+			return monitors
+		}
+	}
+	for i, m := range monitors {
+		if m.X == 0 && m.Y == 0 {
+			monitors[i].Primary = true
+			return monitors
+		}
+	}
+	monitors[0].Primary = true
+	return monitors
+}
+
 // GetPrimary returns the primary monitor
 func GetPrimary() (*Monitor, error) {
 	monitors, err := Detect()
@@ -0,0 +1,75 @@
+//go:build linux
+
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// detect picks a source based on the session type: Hyprland and sway are
+// asked over IPC, anything else with an X display (including XWayland under
+// other compositors) goes through xrandr.
+func detect() ([]Monitor, error) {
+	switch {
+	case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+		return detectHyprland()
+	case os.Getenv("SWAYSOCK") != "":
+		return detectSway()
+	case os.Getenv("DISPLAY") != "":
+		return detectXrandr()
+	default:
+		return nil, fmt.Errorf("no display session found (DISPLAY, SWAYSOCK and HYPRLAND_INSTANCE_SIGNATURE are unset)")
+	}
+}
+
+func detectHyprland() ([]Monitor, error) {
+	out, err := exec.Command("hyprctl", "monitors", "-j").Output()
+	if err != nil {
+		return nil, fmt.Errorf("hyprctl monitors failed: %w", err)
+	}
+	return parseHyprctlMonitors(out)
+}
+
+func detectSway() ([]Monitor, error) {
+	out, err := exec.Command("swaymsg", "-r", "-t", "get_outputs").Output()
+	if err != nil {
+		return nil, fmt.Errorf("swaymsg get_outputs failed: %w", err)
+	}
+	monitors, err := parseSwayOutputs(out)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work areas are best-effort: fall back to full output geometry
+	if ws, err := exec.Command("swaymsg", "-r", "-t", "get_workspaces").Output(); err == nil {
+		monitors, _ = applySwayWorkspaces(monitors, ws)
+	}
+	return monitors, nil
+}
+
+func detectXrandr() ([]Monitor, error) {
+	var monitors []Monitor
+	if out, err := exec.Command("xrandr", "--listmonitors").Output(); err == nil {
+		monitors = parseXrandrListMonitors(string(out))
+	}
+	if len(monitors) == 0 {
+		out, err := exec.Command("xrandr", "--query").Output()
+		if err != nil {
+			return nil, fmt.Errorf("xrandr failed: %w", err)
+		}
+		monitors = parseXrandrQuery(string(out))
+	}
+	if len(monitors) == 0 {
+		return nil, fmt.Errorf("xrandr reported no active monitors")
+	}
+
+	// Work area is best-effort: needs xprop and an EWMH window manager
+	if out, err := exec.Command("xprop", "-root", "_NET_WORKAREA").Output(); err == nil {
+		if area, ok := parseNetWorkArea(string(out)); ok {
+			monitors = clipToWorkArea(monitors, area)
+		}
+	}
+	return monitors, nil
+}
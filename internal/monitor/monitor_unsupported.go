@@ -0,0 +1,13 @@
+//go:build !windows && !linux
+
+package monitor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// detect has no implementation on this platform.
+func detect() ([]Monitor, error) {
+	return nil, fmt.Errorf("monitor detection is not supported on %s", runtime.GOOS)
+}
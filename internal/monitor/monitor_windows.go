@@ -0,0 +1,86 @@
+//go:build windows
+
+package monitor
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                  = syscall.NewLazyDLL("user32.dll")
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
+)
+
+// RECT structure
+type rect struct {
+	Left, Top, Right, Bottom int32
+}
+
+// MONITORINFOEXW structure
+type monitorInfoExW struct {
+	CbSize    uint32
+	RcMonitor rect
+	RcWork    rect
+	DwFlags   uint32
+	SzDevice  [32]uint16
+}
+
+const (
+	MONITORINFOF_PRIMARY = 0x00000001
+)
+
+// detect enumerates monitors via EnumDisplayMonitors, reporting each
+// monitor's work area (excluding the taskbar).
+func detect() ([]Monitor, error) {
+	var monitors []Monitor
+
+	// Callback function for EnumDisplayMonitors
+	callback := syscall.NewCallback(func(hMonitor uintptr, hdcMonitor uintptr, lprcMonitor uintptr, dwData uintptr) uintptr {
+		var info monitorInfoExW
+		info.CbSize = uint32(unsafe.Sizeof(info))
+
+		ret, _, _ := procGetMonitorInfoW.Call(
+			hMonitor,
+			uintptr(unsafe.Pointer(&info)),
+		)
+
+		if ret != 0 {
+			// Convert device name from UTF16 to string
+			deviceName := syscall.UTF16ToString(info.SzDevice[:])
+
+			m := Monitor{
+				Name:    deviceName,
+				X:       int(info.RcWork.Left),
+				Y:       int(info.RcWork.Top),
+				Width:   int(info.RcWork.Right - info.RcWork.Left),
+				Height:  int(info.RcWork.Bottom - info.RcWork.Top),
+				Primary: info.DwFlags&MONITORINFOF_PRIMARY != 0,
+			}
+
+			// Generate a friendly name if device name is technical
+			if m.Name == "" || m.Name[0] == '\\' {
+				m.Name = fmt.Sprintf("Display %d", len(monitors)+1)
+			}
+
+			monitors = append(monitors, m)
+		}
+
+		return 1 // Continue enumeration
+	})
+
+	ret, _, err := procEnumDisplayMonitors.Call(
+		0,        // hdc - NULL for all monitors
+		0,        // lprcClip - NULL for entire virtual screen
+		callback, // lpfnEnum
+		0,        // dwData
+	)
+
+	if ret == 0 {
+		return nil, fmt.Errorf("EnumDisplayMonitors failed: %v", err)
+	}
+
+	return monitors, nil
+}
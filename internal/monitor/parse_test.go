@@ -0,0 +1,115 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func assertMonitors(t *testing.T, got, want []Monitor) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d monitors, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("monitor %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestParseXrandrListMonitors(t *testing.T) {
+	got := parseXrandrListMonitors(string(readFixture(t, "xrandr-listmonitors.txt")))
+	assertMonitors(t, got, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 0, Width: 1920, Height: 1080, Primary: true},
+		{Name: "HDMI-1", X: 1920, Y: 0, Width: 2560, Height: 1440},
+	})
+}
+
+func TestParseXrandrQuery(t *testing.T) {
+	// DP-1 is disconnected and DP-2 is connected but off: both skipped
+	got := parseXrandrQuery(string(readFixture(t, "xrandr-query.txt")))
+	assertMonitors(t, got, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 0, Width: 1920, Height: 1080, Primary: true},
+		{Name: "HDMI-1", X: 1920, Y: 0, Width: 2560, Height: 1440},
+	})
+}
+
+func TestParseXrandrNegativeOffset(t *testing.T) {
+	got := parseXrandrQuery("DP-1 connected 1920x1080+-1920+0 (normal) 0mm x 0mm\n")
+	assertMonitors(t, got, []Monitor{
+		{Name: "DP-1", X: -1920, Y: 0, Width: 1920, Height: 1080, Primary: true},
+	})
+}
+
+func TestClipToNetWorkArea(t *testing.T) {
+	area, ok := parseNetWorkArea(string(readFixture(t, "xprop-workarea.txt")))
+	if !ok {
+		t.Fatal("failed to parse _NET_WORKAREA")
+	}
+	if area != (Monitor{X: 0, Y: 32, Width: 4480, Height: 1408}) {
+		t.Fatalf("unexpected work area %+v", area)
+	}
+
+	monitors := parseXrandrListMonitors(string(readFixture(t, "xrandr-listmonitors.txt")))
+	got := clipToWorkArea(monitors, area)
+	assertMonitors(t, got, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 32, Width: 1920, Height: 1048, Primary: true},
+		{Name: "HDMI-1", X: 1920, Y: 32, Width: 2560, Height: 1408},
+	})
+
+	if _, ok := parseNetWorkArea("_NET_WORKAREA:  not found.\n"); ok {
+		t.Error("expected parse failure when the property is missing")
+	}
+}
+
+func TestParseSwayOutputs(t *testing.T) {
+	monitors, err := parseSwayOutputs(readFixture(t, "sway-outputs.json"))
+	if err != nil {
+		t.Fatalf("parseSwayOutputs failed: %v", err)
+	}
+	// Sway has no primary output: the one at the origin is used
+	assertMonitors(t, monitors, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 0, Width: 1920, Height: 1200, Primary: true},
+		{Name: "DP-3", X: 1920, Y: 0, Width: 2560, Height: 1440},
+	})
+
+	got, err := applySwayWorkspaces(monitors, readFixture(t, "sway-workspaces.json"))
+	if err != nil {
+		t.Fatalf("applySwayWorkspaces failed: %v", err)
+	}
+	assertMonitors(t, got, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 30, Width: 1920, Height: 1170, Primary: true},
+		{Name: "DP-3", X: 1920, Y: 30, Width: 2560, Height: 1410},
+	})
+}
+
+func TestParseHyprctlMonitors(t *testing.T) {
+	got, err := parseHyprctlMonitors(readFixture(t, "hyprctl-monitors.json"))
+	if err != nil {
+		t.Fatalf("parseHyprctlMonitors failed: %v", err)
+	}
+	// eDP-1: 2880x1800 @2x minus a 40px top bar; DP-1: 4K @1.5x rotated 90°
+	assertMonitors(t, got, []Monitor{
+		{Name: "eDP-1", X: 0, Y: 40, Width: 1440, Height: 860, Primary: true},
+		{Name: "DP-1", X: 1440, Y: 0, Width: 1440, Height: 2560},
+	})
+}
+
+func TestParseInvalidJSON(t *testing.T) {
+	if _, err := parseSwayOutputs([]byte("not json")); err == nil {
+		t.Error("expected error from parseSwayOutputs")
+	}
+	if _, err := parseHyprctlMonitors([]byte("not json")); err == nil {
+		t.Error("expected error from parseHyprctlMonitors")
+	}
+}
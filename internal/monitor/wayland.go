@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// swayRect is the geometry object used throughout sway's IPC replies.
+type swayRect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type swayOutput struct {
+	Name   string   `json:"name"`
+	Active bool     `json:"active"`
+	Rect   swayRect `json:"rect"`
+}
+
+type swayWorkspace struct {
+	Output  string   `json:"output"`
+	Visible bool     `json:"visible"`
+	Rect    swayRect `json:"rect"`
+}
+
+// parseSwayOutputs parses `swaymsg -t get_outputs` JSON. Inactive outputs
+// are skipped.
+func parseSwayOutputs(data []byte) ([]Monitor, error) {
+	var outputs []swayOutput
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, fmt.Errorf("failed to parse sway outputs: %w", err)
+	}
+
+	var monitors []Monitor
+	for _, o := range outputs {
+		if !o.Active {
+			continue
+		}
+		monitors = append(monitors, Monitor{
+			Name:   o.Name,
+			X:      o.Rect.X,
+			Y:      o.Rect.Y,
+			Width:  o.Rect.Width,
+			Height: o.Rect.Height,
+		})
+	}
+	return ensurePrimary(monitors), nil
+}
+
+// applySwayWorkspaces narrows each monitor to the rect of its visible
+// workspace (parsed from `swaymsg -t get_workspaces`), which excludes bars.
+func applySwayWorkspaces(monitors []Monitor, data []byte) ([]Monitor, error) {
+	var workspaces []swayWorkspace
+	if err := json.Unmarshal(data, &workspaces); err != nil {
+		return monitors, fmt.Errorf("failed to parse sway workspaces: %w", err)
+	}
+
+	for _, ws := range workspaces {
+		if !ws.Visible || ws.Rect.Width <= 0 || ws.Rect.Height <= 0 {
+			continue
+		}
+		for i := range monitors {
+			if monitors[i].Name == ws.Output {
+				monitors[i].X = ws.Rect.X
+				monitors[i].Y = ws.Rect.Y
+				monitors[i].Width = ws.Rect.Width
+				monitors[i].Height = ws.Rect.Height
+			}
+		}
+	}
+	return monitors, nil
+}
+
+type hyprMonitor struct {
+	Name      string  `json:"name"`
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	Scale     float64 `json:"scale"`
+	Transform int     `json:"transform"`
+	Disabled  bool    `json:"disabled"`
+	Reserved  []int   `json:"reserved"` // left, top, right, bottom
+}
+
+// parseHyprctlMonitors parses `hyprctl monitors -j` JSON. Hyprland reports
+// physical pixel sizes, so they are converted to logical (layout) pixels
+// using the monitor scale and rotation before the reserved areas of bars
+// are subtracted.
+func parseHyprctlMonitors(data []byte) ([]Monitor, error) {
+	var hypr []hyprMonitor
+	if err := json.Unmarshal(data, &hypr); err != nil {
+		return nil, fmt.Errorf("failed to parse hyprctl monitors: %w", err)
+	}
+
+	var monitors []Monitor
+	for _, h := range hypr {
+		if h.Disabled {
+			continue
+		}
+		scale := h.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		width := int(float64(h.Width)/scale + 0.5)
+		height := int(float64(h.Height)/scale + 0.5)
+		if h.Transform%2 == 1 { // 90° and 270° rotations, flipped or not
+			width, height = height, width
+		}
+
+		m := Monitor{Name: h.Name, X: h.X, Y: h.Y, Width: width, Height: height}
+		if len(h.Reserved) == 4 {
+			m.X += h.Reserved[0]
+			m.Y += h.Reserved[1]
+			m.Width -= h.Reserved[0] + h.Reserved[2]
+			m.Height -= h.Reserved[1] + h.Reserved[3]
+		}
+		monitors = append(monitors, m)
+	}
+	return ensurePrimary(monitors), nil
+}
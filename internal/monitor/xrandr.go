@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// " 0: +*eDP-1 1920/344x1080/194+0+0  eDP-1"
+	listMonitorsRe = regexp.MustCompile(`^\s*\d+:\s+[+]?(\*?)(\S+)\s+(\d+)/\d+x(\d+)/\d+\+(-?\d+)\+(-?\d+)`)
+	// "eDP-1 connected primary 1920x1080+0+0 (normal left ...) 344mm x 194mm"
+	queryRe = regexp.MustCompile(`^(\S+) connected (primary )?(\d+)x(\d+)\+(-?\d+)\+(-?\d+)`)
+)
+
+// parseXrandrListMonitors parses `xrandr --listmonitors` output.
+func parseXrandrListMonitors(out string) []Monitor {
+	var monitors []Monitor
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		match := listMonitorsRe.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		monitors = append(monitors, Monitor{
+			Name:    match[2],
+			Width:   atoi(match[3]),
+			Height:  atoi(match[4]),
+			X:       atoi(match[5]),
+			Y:       atoi(match[6]),
+			Primary: match[1] == "*",
+		})
+	}
+	return ensurePrimary(monitors)
+}
+
+// parseXrandrQuery parses `xrandr --query` output. Outputs that are
+// connected but switched off have no geometry and are skipped.
+func parseXrandrQuery(out string) []Monitor {
+	var monitors []Monitor
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		match := queryRe.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		monitors = append(monitors, Monitor{
+			Name:    match[1],
+			Primary: match[2] != "",
+			Width:   atoi(match[3]),
+			Height:  atoi(match[4]),
+			X:       atoi(match[5]),
+			Y:       atoi(match[6]),
+		})
+	}
+	return ensurePrimary(monitors)
+}
+
+// parseNetWorkArea parses `xprop -root _NET_WORKAREA` output and returns the
+// work area of the first desktop. X11 only publishes one work area for the
+// whole screen, so it is intersected with each monitor by clipToWorkArea.
+func parseNetWorkArea(out string) (Monitor, bool) {
+	_, values, ok := strings.Cut(out, "=")
+	if !ok {
+		return Monitor{}, false
+	}
+	fields := strings.Split(values, ",")
+	if len(fields) < 4 {
+		return Monitor{}, false
+	}
+	var n [4]int
+	for i := range n {
+		v, err := strconv.Atoi(strings.TrimSpace(fields[i]))
+		if err != nil {
+			return Monitor{}, false
+		}
+		n[i] = v
+	}
+	return Monitor{X: n[0], Y: n[1], Width: n[2], Height: n[3]}, true
+}
+
+// clipToWorkArea shrinks each monitor to its overlap with the work area so
+// panels and docks along the screen edges are excluded. Monitors that don't
+// overlap the work area are left unchanged.
+func clipToWorkArea(monitors []Monitor, area Monitor) []Monitor {
+	for i, m := range monitors {
+		left := max(m.X, area.X)
+		top := max(m.Y, area.Y)
+		right := min(m.X+m.Width, area.X+area.Width)
+		bottom := min(m.Y+m.Height, area.Y+area.Height)
+		if right <= left || bottom <= top {
+			continue
+		}
+		monitors[i].X = left
+		monitors[i].Y = top
+		monitors[i].Width = right - left
+		monitors[i].Height = bottom - top
+	}
+	return monitors
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,253 @@
+// Package scaffold creates new projects from config.Template definitions:
+// a copied directory or a cloned repository, seed files such as CLAUDE.md,
+// and post-create commands, with {{var}} substitution throughout.
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bcmister/qs/internal/config"
+)
+
+// varPattern matches {{name}}, with optional spaces inside the braces.
+var varPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// binarySniffLen is how much of a file is checked for NUL bytes before
+// substituting variables; files that have them are copied unchanged.
+const binarySniffLen = 8000
+
+// Vars returns the variables for a new project at dir: name, dir, parent,
+// date, year and user, then the template's own Vars, which may use the
+// built-in ones.
+func Vars(dir string, t config.Template) map[string]string {
+	now := time.Now()
+	vars := map[string]string{
+		"name":   filepath.Base(dir),
+		"dir":    dir,
+		"parent": filepath.Dir(dir),
+		"date":   now.Format("2006-01-02"),
+		"year":   now.Format("2006"),
+	}
+	if u, err := user.Current(); err == nil {
+		vars["user"] = filepath.Base(u.Username) // DOMAIN\user on Windows
+	}
+	for k, v := range t.Vars {
+		vars[k] = Expand(v, vars)
+	}
+	return vars
+}
+
+// Expand replaces the {{var}} references in s that vars defines. Unknown
+// ones are left alone, so files that use braces themselves survive.
+func Expand(s string, vars map[string]string) string {
+	return varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := vars[varPattern.FindStringSubmatch(ref)[1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// Create makes the project directory dir from t. dir must not exist yet;
+// if any step fails it is removed again, so the name can be reused.
+func Create(dir string, t config.Template, vars map[string]string) (err error) {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	switch {
+	case t.Path != "":
+		src := expandHome(Expand(t.Path, vars))
+		if err := copyTree(src, dir, vars); err != nil {
+			return fmt.Errorf("copy template %s: %w", src, err)
+		}
+	case t.Git != "":
+		if err := cloneTree(Expand(t.Git, vars), dir, vars); err != nil {
+			return err
+		}
+	}
+
+	for name, content := range t.Files {
+		rel := filepath.FromSlash(Expand(name, vars))
+		path := filepath.Join(dir, rel)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("template file %q is outside the project", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(Expand(content, vars)), 0644); err != nil {
+			return err
+		}
+	}
+
+	quoted := shellQuoted(vars)
+	for _, command := range t.Commands {
+		if err := run(dir, Expand(command, quoted), vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellQuoted returns vars with each value quoted for the shell that runs
+// commands, so a folder name like "my app" stays a single argument and
+// can't inject commands of its own.
+func shellQuoted(vars map[string]string) map[string]string {
+	quoted := make(map[string]string, len(vars))
+	for k, v := range vars {
+		quoted[k] = shellQuote(v)
+	}
+	return quoted
+}
+
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		// cmd has no escape for a quote inside quotes, and file names can't
+		// contain one
+		return `"` + strings.ReplaceAll(s, `"`, "") + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commandEnv returns the environment commands run in: qs's own plus each
+// variable as QS_<NAME>, e.g. QS_NAME and QS_DIR.
+func commandEnv(vars map[string]string) []string {
+	env := os.Environ()
+	for k, v := range vars {
+		name := strings.Map(func(r rune) rune {
+			if r == '-' || r == '.' {
+				return '_'
+			}
+			return unicode.ToUpper(r)
+		}, k)
+		env = append(env, "QS_"+name+"="+v)
+	}
+	return env
+}
+
+// cloneTree clones url and copies its files into dst without the
+// repository's history: the new project starts its own.
+func cloneTree(url, dst string, vars map[string]string) error {
+	tmp, err := os.MkdirTemp("", "qs-template-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "src")
+	out, err := exec.Command("git", "clone", "--depth", "1", "--quiet", url, src).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git clone %s: %s", url, lastLine(out, err))
+	}
+	if err := copyTree(src, dst, vars); err != nil {
+		return fmt.Errorf("copy template %s: %w", url, err)
+	}
+	return nil
+}
+
+// copyTree copies src into dst, expanding variables in file and directory
+// names and in the contents of text files. .git directories are skipped.
+func copyTree(src, dst string, vars map[string]string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, Expand(rel, vars))
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(data[:min(len(data), binarySniffLen)], []byte{0}) {
+			data = []byte(Expand(string(data), vars))
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
+
+// run runs a post-create command through the shell in dir, with vars in
+// its environment.
+func run(dir, command string, vars map[string]string) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Dir = dir
+	c.Env = commandEnv(vars)
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", command, lastLine(out, err))
+	}
+	return nil
+}
+
+// lastLine returns the last line of a failed command's output, which is
+// usually the error, or err itself when there was no output.
+func lastLine(out []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
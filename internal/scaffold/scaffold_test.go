@@ -0,0 +1,154 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"name": "api", "org": "acme"}
+	got := Expand("module github.com/{{org}}/{{ name }} {{unknown}} {{.Field}}", vars)
+	if got != "module github.com/acme/api {{unknown}} {{.Field}}" {
+		t.Errorf("unexpected expansion %q", got)
+	}
+}
+
+func TestVars(t *testing.T) {
+	vars := Vars(filepath.Join("work", "api"), config.Template{Vars: map[string]string{"module": "example.com/{{name}}"}})
+	if vars["name"] != "api" || vars["module"] != "example.com/api" || vars["year"] == "" {
+		t.Errorf("unexpected vars %v", vars)
+	}
+}
+
+func TestCreateFromDirectory(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "README.md"), "# {{name}}\n")
+	writeFile(t, filepath.Join(src, "cmd", "{{name}}", "main.go"), "package main // {{name}}\n")
+	writeFile(t, filepath.Join(src, "logo.bin"), "\x00{{name}}")
+	writeFile(t, filepath.Join(src, ".git", "HEAD"), "ref: refs/heads/main\n")
+
+	dir := filepath.Join(t.TempDir(), "api")
+	tmpl := config.Template{
+		Name:     "go",
+		Path:     src,
+		Files:    map[string]string{"CLAUDE.md": "Project {{name}}.\n", "docs/AGENTS.md": "{{name}} agents\n"},
+		Commands: []string{"echo {{name}}> marker.txt"},
+	}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"README.md":                            "# api\n",
+		filepath.Join("cmd", "api", "main.go"): "package main // api\n",
+		"logo.bin":                             "\x00{{name}}",
+		"CLAUDE.md":                            "Project api.\n",
+		filepath.Join("docs", "AGENTS.md"):     "api agents\n",
+	} {
+		if got := readFile(t, filepath.Join(dir, path)); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if got := strings.TrimSpace(readFile(t, filepath.Join(dir, "marker.txt"))); got != "api" {
+		t.Errorf("expected the command to run in the project, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Error("expected the template's .git to be skipped")
+	}
+}
+
+func TestCreateQuotesCommandVars(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	dir := filepath.Join(t.TempDir(), "my app;touch pwned")
+	tmpl := config.Template{
+		Name:     "quoted",
+		Commands: []string{"echo {{name}} > marker.txt", `echo "$QS_NAME" > env.txt`},
+	}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, file := range []string{"marker.txt", "env.txt"} {
+		if got := strings.TrimSpace(readFile(t, filepath.Join(dir, file))); got != "my app;touch pwned" {
+			t.Errorf("%s = %q, want the folder name as one argument", file, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwned")); !os.IsNotExist(err) {
+		t.Error("the folder name ran a command")
+	}
+}
+
+func TestCreateRemovesProjectOnFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "api")
+	tmpl := config.Template{Name: "broken", Commands: []string{"exit 3"}}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err == nil {
+		t.Fatal("expected the failing command to fail Create")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("expected the half-created project to be removed")
+	}
+
+	tmpl = config.Template{Name: "escape", Files: map[string]string{"../outside.txt": "x"}}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err == nil {
+		t.Error("expected a file outside the project to be rejected")
+	}
+	tmpl = config.Template{Name: "both", Path: "a", Git: "b"}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err == nil {
+		t.Error("expected path and git together to be rejected")
+	}
+}
+
+func TestCreateFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "go.mod"), "module example.com/{{name}}\n")
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=qs", "-c", "user.email=qs@example.com", "commit", "--quiet", "-m", "template"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "svc")
+	tmpl := config.Template{Name: "svc", Git: repo}
+	if err := Create(dir, tmpl, Vars(dir, tmpl)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "go.mod")); got != "module example.com/svc\n" {
+		t.Errorf("go.mod = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Error("expected the clone's history to be left behind")
+	}
+}
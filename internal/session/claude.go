@@ -0,0 +1,111 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// claudeReader reads Claude Code's transcripts:
+// <config dir>/projects/<encoded cwd>/<session id>.jsonl.
+type claudeReader struct{}
+
+// claudeProjectChars are the characters Claude Code replaces with '-' when
+// naming a project's transcript directory after its path.
+var claudeProjectChars = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+type claudeEntry struct {
+	Type      string    `json:"type"`
+	IsMeta    bool      `json:"isMeta"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+func (claudeReader) List(dir string, env map[string]string) ([]Session, error) {
+	projects := filepath.Join(dataDir(env, "CLAUDE_CONFIG_DIR", ".claude"), "projects")
+	files, err := filepath.Glob(filepath.Join(projects, claudeProjectChars.ReplaceAllString(dir, "-"), "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, file := range files {
+		s, ok := readClaudeSession(file)
+		if ok {
+			sessions = append(sessions, s)
+		}
+	}
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+func (claudeReader) ResumeArgs(id string, args []string) []string {
+	return append(append([]string(nil), args...), "--resume", id)
+}
+
+func readClaudeSession(file string) (Session, bool) {
+	s := Session{ID: strings.TrimSuffix(filepath.Base(file), ".jsonl")}
+	err := forEachLine(file, func(line []byte) bool {
+		var e claudeEntry
+		if json.Unmarshal(line, &e) != nil || (e.Type != "user" && e.Type != "assistant") || e.IsMeta {
+			return true
+		}
+		if !e.Timestamp.IsZero() {
+			if s.Started.IsZero() {
+				s.Started = e.Timestamp
+			}
+			s.Updated = e.Timestamp
+		}
+
+		text, toolResult := claudeText(e.Message.Content)
+		if e.Type == "user" && toolResult {
+			return true
+		}
+		s.Messages++
+		if e.Type == "user" && s.FirstMessage == "" {
+			s.FirstMessage = cleanMessage(text)
+		}
+		return true
+	})
+	if err != nil || s.Messages == 0 {
+		return Session{}, false
+	}
+	if s.Updated.IsZero() {
+		if info, err := os.Stat(file); err == nil {
+			s.Started, s.Updated = info.ModTime(), info.ModTime()
+		}
+	}
+	return s, true
+}
+
+// claudeText returns a message's text and whether it only carries tool
+// results, which Claude Code records as user messages.
+func claudeText(content json.RawMessage) (string, bool) {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text, false
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &blocks) != nil {
+		return "", false
+	}
+	toolResult := len(blocks) > 0
+	var parts []string
+	for _, b := range blocks {
+		if b.Type != "tool_result" {
+			toolResult = false
+		}
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, " "), toolResult
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// codexReader reads Codex rollouts:
+// $CODEX_HOME/sessions/YYYY/MM/DD/rollout-<time>-<id>.jsonl, whose first
+// line records the session id and working directory.
+type codexReader struct{}
+
+type codexLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Payload   struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`
+		Cwd     string `json:"cwd"`
+		Role    string `json:"role"`
+		Message string `json:"message"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"payload"`
+}
+
+func (codexReader) List(dir string, env map[string]string) ([]Session, error) {
+	root := filepath.Join(dataDir(env, "CODEX_HOME", ".codex"), "sessions")
+
+	var sessions []Session
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "rollout-") || !strings.HasSuffix(d.Name(), ".jsonl") {
+			return nil
+		}
+		if s, ok := readCodexSession(path, dir); ok {
+			sessions = append(sessions, s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+func (codexReader) ResumeArgs(id string, args []string) []string {
+	return append([]string{"resume", id}, args...)
+}
+
+// readCodexSession reads a rollout if it was started in dir. Only the first
+// line is read for rollouts from other directories.
+func readCodexSession(file, dir string) (Session, bool) {
+	var s Session
+	match := false
+	firstEvent := ""
+	firstItem := ""
+	forEachLine(file, func(line []byte) bool {
+		var l codexLine
+		if json.Unmarshal(line, &l) != nil {
+			return true
+		}
+		if !match {
+			if l.Type != "session_meta" || !sameDir(l.Payload.Cwd, dir) {
+				return false
+			}
+			match = true
+			s.ID = l.Payload.ID
+			s.Started, s.Updated = l.Timestamp, l.Timestamp
+			return true
+		}
+
+		if !l.Timestamp.IsZero() {
+			s.Updated = l.Timestamp
+		}
+		switch {
+		case l.Type == "event_msg" && l.Payload.Type == "user_message":
+			if firstEvent == "" {
+				firstEvent = cleanMessage(l.Payload.Message)
+			}
+		case l.Type == "response_item" && l.Payload.Type == "message":
+			var parts []string
+			for _, c := range l.Payload.Content {
+				parts = append(parts, c.Text)
+			}
+			text := cleanMessage(strings.Join(parts, " "))
+			if text == "" || (l.Payload.Role != "user" && l.Payload.Role != "assistant") {
+				return true
+			}
+			s.Messages++
+			if l.Payload.Role == "user" && firstItem == "" {
+				firstItem = text
+			}
+		}
+		return true
+	})
+	if !match || s.ID == "" {
+		return Session{}, false
+	}
+	s.FirstMessage = firstEvent
+	if s.FirstMessage == "" {
+		s.FirstMessage = firstItem
+	}
+	return s, true
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// geminiReader reads Gemini CLI checkpoints:
+// ~/.gemini/tmp/<sha256 of the project dir>/chats/session-*.json.
+type geminiReader struct{}
+
+type geminiFile struct {
+	SessionID   string    `json:"sessionId"`
+	StartTime   time.Time `json:"startTime"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	Messages    []struct {
+		Type    string          `json:"type"`
+		Content json.RawMessage `json:"content"`
+	} `json:"messages"`
+}
+
+func (geminiReader) List(dir string, env map[string]string) ([]Session, error) {
+	homeDir, _ := os.UserHomeDir()
+	hash := sha256.Sum256([]byte(dir))
+	chats := filepath.Join(homeDir, ".gemini", "tmp", hex.EncodeToString(hash[:]), "chats")
+	files, err := filepath.Glob(filepath.Join(chats, "session-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, file := range files {
+		if s, ok := readGeminiSession(file); ok {
+			sessions = append(sessions, s)
+		}
+	}
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+func (geminiReader) ResumeArgs(id string, args []string) []string {
+	return append(append([]string(nil), args...), "--resume", id)
+}
+
+func readGeminiSession(file string) (Session, bool) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Session{}, false
+	}
+	var g geminiFile
+	if json.Unmarshal(data, &g) != nil || g.SessionID == "" {
+		return Session{}, false
+	}
+
+	s := Session{ID: g.SessionID, Started: g.StartTime, Updated: g.LastUpdated}
+	for _, msg := range g.Messages {
+		if msg.Type != "user" && msg.Type != "gemini" {
+			continue
+		}
+		s.Messages++
+		if msg.Type == "user" && s.FirstMessage == "" {
+			s.FirstMessage = cleanMessage(geminiText(msg.Content))
+		}
+	}
+	if s.Messages == 0 {
+		return Session{}, false
+	}
+	return s, true
+}
+
+// geminiText returns a message's text: a plain string or a list of parts.
+func geminiText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	json.Unmarshal(content, &parts)
+	var out []string
+	for _, p := range parts {
+		out = append(out, p.Text)
+	}
+	return strings.Join(out, " ")
+}
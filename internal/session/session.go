@@ -0,0 +1,127 @@
+// Package session reads the session history AI coding tools keep on disk,
+// so a previous conversation in a project can be resumed from the picker.
+// Each tool has its own store and resume flag; readers are registered per
+// account command.
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Session is one stored conversation.
+type Session struct {
+	ID           string
+	Started      time.Time
+	Updated      time.Time
+	FirstMessage string // the first thing the user typed
+	Messages     int    // user and assistant messages
+}
+
+// Reader lists a tool's stored sessions and builds its resume command line.
+type Reader interface {
+	// List returns the sessions started in dir, most recently updated
+	// first. env holds the account's extra environment, which may move the
+	// tool's data directory (e.g. CLAUDE_CONFIG_DIR).
+	List(dir string, env map[string]string) ([]Session, error)
+
+	// ResumeArgs returns the arguments that resume session id, given the
+	// account's usual arguments.
+	ResumeArgs(id string, args []string) []string
+}
+
+var readers = map[string]Reader{
+	"claude": claudeReader{},
+	"codex":  codexReader{},
+	"gemini": geminiReader{},
+}
+
+// Register adds or replaces the reader for an account command.
+func Register(command string, r Reader) {
+	readers[command] = r
+}
+
+// ReaderFor returns the reader for an account command, if there is one.
+func ReaderFor(command string) (Reader, bool) {
+	r, ok := readers[filepath.Base(command)]
+	return r, ok
+}
+
+// dataDir returns a tool's data directory: the env var from the account's
+// environment or the process environment, else ~/<fallback>.
+func dataDir(env map[string]string, envVar, fallback string) string {
+	if dir := env[envVar]; dir != "" {
+		return dir
+	}
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, fallback)
+}
+
+// sortSessions orders sessions most recently updated first.
+func sortSessions(sessions []Session) {
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Updated.After(sessions[j].Updated)
+	})
+}
+
+// forEachLine calls fn with each line of a JSONL file. Lines can be far
+// longer than bufio.Scanner's default limit, so they're read whole.
+func forEachLine(path string, fn func([]byte) bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if !fn(line) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// cleanMessage flattens a message to one line for display. Messages the
+// tool injected itself (XML-ish context blocks) are reported as empty.
+func cleanMessage(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if strings.HasPrefix(s, "<") {
+		return ""
+	}
+	return s
+}
+
+// sameDir reports whether two paths name the same directory.
+func sameDir(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if a == b {
+		return true
+	}
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		if rb, err := filepath.EvalSymlinks(b); err == nil {
+			return ra == rb
+		}
+	}
+	return false
+}
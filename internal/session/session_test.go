@@ -0,0 +1,138 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeReader(t *testing.T) {
+	configDir := t.TempDir()
+	project := "/home/me/.1dev/api"
+	dir := filepath.Join(configDir, "projects", "-home-me--1dev-api")
+
+	writeFile(t, filepath.Join(dir, "old.jsonl"), `{"type":"user","timestamp":"2026-01-01T10:00:00Z","message":{"role":"user","content":"first session"}}
+{"type":"assistant","timestamp":"2026-01-01T10:01:00Z","message":{"role":"assistant","content":[{"type":"text","text":"ok"}]}}
+`)
+	writeFile(t, filepath.Join(dir, "new.jsonl"), `{"type":"summary","summary":"x"}
+{"type":"user","isMeta":true,"timestamp":"2026-02-01T09:00:00Z","message":{"role":"user","content":"<local-command-caveat>"}}
+{"type":"user","timestamp":"2026-02-01T09:00:00Z","message":{"role":"user","content":[{"type":"text","text":"fix the\nlogin bug"}]}}
+{"type":"assistant","timestamp":"2026-02-01T09:01:00Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1"}]}}
+{"type":"user","timestamp":"2026-02-01T09:02:00Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+{"type":"assistant","timestamp":"2026-02-01T09:03:00Z","message":{"role":"assistant","content":[{"type":"text","text":"done"}]}}
+`)
+
+	sessions, err := claudeReader{}.List(project, map[string]string{"CLAUDE_CONFIG_DIR": configDir})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "new" || sessions[1].ID != "old" {
+		t.Fatalf("expected new then old, got %+v", sessions)
+	}
+	s := sessions[0]
+	if s.FirstMessage != "fix the login bug" || s.Messages != 3 {
+		t.Errorf("unexpected session %+v", s)
+	}
+	if s.Updated.Format("15:04") != "09:03" {
+		t.Errorf("expected last timestamp as Updated, got %v", s.Updated)
+	}
+
+	args := claudeReader{}.ResumeArgs("new", []string{"--effort", "max"})
+	if strings.Join(args, " ") != "--effort max --resume new" {
+		t.Errorf("unexpected resume args %v", args)
+	}
+}
+
+func TestCodexReader(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	day := filepath.Join(home, "sessions", "2026", "02", "01")
+
+	writeFile(t, filepath.Join(day, "rollout-2026-02-01T09-00-00-abc.jsonl"), `{"timestamp":"2026-02-01T09:00:00Z","type":"session_meta","payload":{"id":"abc","cwd":"`+filepath.ToSlash(project)+`"}}
+{"timestamp":"2026-02-01T09:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"<environment_context>cwd</environment_context>"}]}}
+{"timestamp":"2026-02-01T09:00:02Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"add tests"}]}}
+{"timestamp":"2026-02-01T09:00:02Z","type":"event_msg","payload":{"type":"user_message","message":"add tests"}}
+{"timestamp":"2026-02-01T09:05:00Z","type":"response_item","payload":{"type":"message","role":"assistant","content":[{"type":"output_text","text":"added"}]}}
+`)
+	writeFile(t, filepath.Join(day, "rollout-2026-02-01T10-00-00-other.jsonl"), `{"timestamp":"2026-02-01T10:00:00Z","type":"session_meta","payload":{"id":"other","cwd":"/elsewhere"}}
+`)
+
+	sessions, err := codexReader{}.List(project, map[string]string{"CODEX_HOME": home})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("expected only the session started in the project, got %+v", sessions)
+	}
+	s := sessions[0]
+	if s.ID != "abc" || s.FirstMessage != "add tests" || s.Messages != 2 || s.Updated.Format("15:04") != "09:05" {
+		t.Errorf("unexpected session %+v", s)
+	}
+
+	if args := (codexReader{}).ResumeArgs("abc", []string{"--yolo"}); strings.Join(args, " ") != "resume abc --yolo" {
+		t.Errorf("unexpected resume args %v", args)
+	}
+
+	// A missing store is not an error
+	if sessions, err := (codexReader{}).List(project, map[string]string{"CODEX_HOME": t.TempDir()}); err != nil || len(sessions) != 0 {
+		t.Errorf("expected no sessions, got %v, %v", sessions, err)
+	}
+}
+
+func TestGeminiReader(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	project := "/home/me/api"
+	hash := sha256.Sum256([]byte(project))
+	chats := filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(hash[:]), "chats")
+
+	writeFile(t, filepath.Join(chats, "session-2026-02-01-abcd.json"), `{
+  "sessionId": "abcd-1234",
+  "startTime": "2026-02-01T09:00:00Z",
+  "lastUpdated": "2026-02-01T09:10:00Z",
+  "messages": [
+    {"type": "user", "content": "explain the parser"},
+    {"type": "gemini", "content": "sure"},
+    {"type": "info", "content": "ignored"}
+  ]
+}`)
+
+	sessions, err := geminiReader{}.List(project, nil)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "abcd-1234" || sessions[0].FirstMessage != "explain the parser" || sessions[0].Messages != 2 {
+		t.Fatalf("unexpected sessions %+v", sessions)
+	}
+}
+
+func TestReaderFor(t *testing.T) {
+	for _, cmd := range []string{"claude", "codex", "gemini", "/usr/local/bin/claude"} {
+		if _, ok := ReaderFor(cmd); !ok {
+			t.Errorf("expected a reader for %s", cmd)
+		}
+	}
+	if _, ok := ReaderFor("agent"); ok {
+		t.Error("expected no reader for agent")
+	}
+
+	Register("agent", claudeReader{})
+	defer delete(readers, "agent")
+	if _, ok := ReaderFor("agent"); !ok {
+		t.Error("expected a registered reader")
+	}
+}
@@ -91,8 +91,9 @@ type keyEntry struct {
 	value string
 }
 
-// NewAccounts creates a new account management model
-func NewAccounts(cfg *config.Config) AccountsModel {
+// NewAccounts creates a new account management model. keys must be the
+// loaded keys file, since edits are saved back over it.
+func NewAccounts(cfg *config.Config, keys config.AccountKeys) AccountsModel {
 	accounts := make([]config.Account, len(cfg.Accounts))
 	for i, a := range cfg.Accounts {
 		accounts[i] = config.Account{
@@ -105,11 +106,11 @@ func NewAccounts(cfg *config.Config) AccountsModel {
 			Icon:       a.Icon,
 			Enabled:    a.Enabled,
 			AuthUser:   a.AuthUser,
+			PromptArgs: a.PromptArgs.Clone(),
+			Env:        a.Env.Clone(),
 		}
 	}
 
-	keys, _ := config.LoadKeys()
-
 	return AccountsModel{
 		cfg:      cfg,
 		accounts: accounts,
@@ -137,8 +138,7 @@ func (m AccountsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			accountID := msg.accountID
 			a := config.AccountByID(m.accounts, accountID)
 			if a != nil {
-				env := accountEnvSlice(m.keys, accountID)
-				return m, probeAuthCmd(accountID, a.Command, env)
+				return m, probeAuthCmd(m.cfg, m.keys, *a)
 			}
 			m.message = "Auth completed successfully"
 		}
@@ -216,7 +216,10 @@ func (m AccountsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			cmd, args := a.AuthCommand()
 			c := exec.Command(cmd, args...)
-			applyAccountEnv(c, m.keys, a.ID)
+			if err := applyAccountEnv(c, m.cfg, m.keys, a.ID); err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
 			accountID := a.ID
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
 				return authDoneMsg{err: err, accountID: accountID}
@@ -230,7 +233,10 @@ func (m AccountsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			cmd, args := a.InstallCommand()
 			c := exec.Command(cmd, args...)
-			applyAccountEnv(c, m.keys, a.ID)
+			if err := applyAccountEnv(c, m.cfg, m.keys, a.ID); err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
 				return installDoneMsg{err: err}
 			})
@@ -414,7 +420,10 @@ func (m AccountsModel) updateAddLabel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			cmd, args := newAcct.AuthCommand()
 			c := exec.Command(cmd, args...)
-			applyAccountEnv(c, m.keys, newAcct.ID)
+			if err := applyAccountEnv(c, m.cfg, m.keys, newAcct.ID); err != nil {
+				m.message = err.Error()
+				return m, nil
+			}
 			accountID := newAcct.ID
 			m.message = ""
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
@@ -642,7 +651,7 @@ func (m AccountsModel) View() string {
 				s.WriteString(fmt.Sprintf("  %s %s  %s\n",
 					prefix,
 					nameStr,
-					DimStyle.Render(config.MaskValue(entry.value))))
+					DimStyle.Render(config.DisplayKeyValue(m.cfg, entry.value))))
 			}
 		}
 		s.WriteString("\n  " + DimStyle.Render("a add  d delete  Esc back") + "\n")
@@ -968,35 +977,55 @@ func truncate(s string, max int) string {
 	return s[:max-3] + "..."
 }
 
-// applyAccountEnv injects account API keys as env vars into the command.
-func applyAccountEnv(c *exec.Cmd, keys config.AccountKeys, accountID string) {
-	accountKeys := config.KeysForAccount(keys, accountID)
-	if len(accountKeys) > 0 {
-		if c.Env == nil {
-			c.Env = os.Environ()
-		}
-		for k, v := range accountKeys {
-			c.Env = append(c.Env, k+"="+v)
-		}
+// applyAccountEnv injects account API keys as env vars into the command,
+// resolving secret references. Login and install commands inherit the
+// whole environment; the account's env policy is for launches.
+func applyAccountEnv(c *exec.Cmd, cfg *config.Config, keys config.AccountKeys, accountID string) error {
+	return applyLaunchEnv(c, cfg, keys, config.Account{ID: accountID}, "")
+}
+
+// applyLaunchEnv sets the environment of a launched tool: what the
+// account's env policy inherits, plus its API keys and the env of the
+// project containing dir, with secret references resolved.
+func applyLaunchEnv(c *exec.Cmd, cfg *config.Config, keys config.AccountKeys, account config.Account, dir string) error {
+	vars, err := config.LaunchEnv(cfg, keys, account.ID, dir)
+	if err != nil {
+		return err
 	}
+	c.Env, err = buildLaunchEnv(cfg, account, vars)
+	return err
 }
 
-// accountEnvSlice returns env vars for an account as a []string slice.
-func accountEnvSlice(keys config.AccountKeys, accountID string) []string {
-	ak := config.KeysForAccount(keys, accountID)
-	var env []string
-	for k, v := range ak {
-		env = append(env, k+"="+v)
+// buildLaunchEnv resolves vars and returns the whole environment of one of
+// account's commands, or nil when it can inherit qs's as-is.
+func buildLaunchEnv(cfg *config.Config, account config.Account, vars []config.EnvVar) ([]string, error) {
+	env, err := config.ResolveEnv(cfg, vars)
+	if err != nil {
+		return nil, err
 	}
-	return env
+	if len(env) == 0 && len(account.Env.Allow) == 0 && len(account.Env.Deny) == 0 {
+		return nil, nil
+	}
+	return config.BuildEnv(os.Environ(), account.Env, env), nil
 }
 
-// probeAuthCmd returns a tea.Cmd that probes auth status for the given account.
-func probeAuthCmd(accountID, command string, env []string) tea.Cmd {
+// probeAuthCmd returns a tea.Cmd that probes auth status for the given
+// account, in the environment its launches get. The keys are read here,
+// since the model may change them, but resolving references can take
+// seconds and happens in the command.
+func probeAuthCmd(cfg *config.Config, keys config.AccountKeys, account config.Account) tea.Cmd {
+	vars, err := config.LaunchEnv(cfg, keys, account.ID, "")
 	return func() tea.Msg {
-		email, org, err := config.ProbeAuthUser(command, env)
+		if err != nil {
+			return authProbeMsg{accountID: account.ID, err: err}
+		}
+		env, err := buildLaunchEnv(cfg, account, vars)
+		if err != nil {
+			return authProbeMsg{accountID: account.ID, err: err}
+		}
+		email, org, err := config.ProbeAuthUser(account.Command, env)
 		return authProbeMsg{
-			accountID: accountID,
+			accountID: account.ID,
 			email:     email,
 			org:       org,
 			err:       err,
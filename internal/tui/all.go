@@ -20,6 +20,17 @@ type AllModel struct {
 	monitorIdx   int
 	windowCounts []int
 
+	// Assign step: per-monitor window assignments, seeded from config.
+	// Slices may be longer than the window count; extra entries are kept so
+	// lowering and raising a count doesn't lose an assignment.
+	windows       [][]config.WindowConfig
+	assigning     bool
+	windowIdx     int
+	choosing      bool
+	projects      []string // project refs across all roots, as stored in WindowConfig.Project
+	projectFilter string
+	projectIdx    int
+
 	// Results
 	confirmed bool
 	quitting  bool
@@ -41,6 +52,41 @@ func (m AllModel) Confirmed() bool { return m.confirmed }
 // WindowCounts returns the per-monitor window counts.
 func (m AllModel) WindowCounts() []int { return m.windowCounts }
 
+// Windows returns the window configs for each monitor, one per window to
+// launch. Windows without a project open the picker.
+func (m AllModel) Windows() [][]config.WindowConfig {
+	out := make([][]config.WindowConfig, len(m.windowCounts))
+	for i, count := range m.windowCounts {
+		out[i] = make([]config.WindowConfig, count)
+		for j := range out[i] {
+			out[i][j] = m.windowAt(i, j)
+		}
+	}
+	return out
+}
+
+// windowAt returns the assignment for a window, falling back to the config.
+func (m AllModel) windowAt(monIdx, winIdx int) config.WindowConfig {
+	if monIdx < len(m.windows) && winIdx < len(m.windows[monIdx]) {
+		return m.windows[monIdx][winIdx]
+	}
+	if monIdx < len(m.cfg.Monitors) {
+		return m.cfg.Monitors[monIdx].WindowAt(winIdx)
+	}
+	return config.WindowConfig{}
+}
+
+// setWindow stores the assignment for a window, growing the slices as needed.
+func (m *AllModel) setWindow(monIdx, winIdx int, w config.WindowConfig) {
+	for len(m.windows) <= monIdx {
+		m.windows = append(m.windows, nil)
+	}
+	for len(m.windows[monIdx]) <= winIdx {
+		m.windows[monIdx] = append(m.windows[monIdx], m.windowAt(monIdx, len(m.windows[monIdx])))
+	}
+	m.windows[monIdx][winIdx] = w
+}
+
 func (m AllModel) totalWindows() int {
 	total := 0
 	for _, c := range m.windowCounts {
@@ -75,6 +121,12 @@ func (m AllModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		switch {
+		case m.choosing:
+			return m.updateChooseProject(msg)
+		case m.assigning:
+			return m.updateAssign(msg)
+		}
 		return m.updateMonitors(msg)
 	}
 
@@ -111,6 +163,118 @@ func (m AllModel) updateMonitors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.monitors) > 0 && m.windowCounts[m.monitorIdx] > 0 {
 			m.windowCounts[m.monitorIdx]--
 		}
+	case msg.String() == "a":
+		if len(m.monitors) > 0 && m.windowCounts[m.monitorIdx] > 0 {
+			m.assigning = true
+			m.windowIdx = 0
+		}
+	}
+	return m, nil
+}
+
+// updateAssign handles the per-window assignment list for the selected monitor.
+func (m AllModel) updateAssign(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := m.windowCounts[m.monitorIdx]
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case msg.Type == tea.KeyEsc:
+		m.assigning = false
+	case msg.Type == tea.KeyUp || msg.String() == "k":
+		if m.windowIdx > 0 {
+			m.windowIdx--
+		}
+	case msg.Type == tea.KeyDown || msg.String() == "j":
+		if m.windowIdx < count-1 {
+			m.windowIdx++
+		}
+	case msg.Type == tea.KeyEnter:
+		m.choosing = true
+		if m.projects == nil {
+			for _, p := range ListProjects(m.cfg) {
+				m.projects = append(m.projects, m.cfg.ProjectRef(p.Dir))
+			}
+		}
+		m.projectFilter = ""
+		m.projectIdx = 0
+	case msg.String() == "t":
+		w := m.windowAt(m.monitorIdx, m.windowIdx)
+		w.Tool = m.nextTool(w.Tool)
+		m.setWindow(m.monitorIdx, m.windowIdx, w)
+	case msg.String() == "x":
+		w := m.windowAt(m.monitorIdx, m.windowIdx)
+		w.Project = ""
+		m.setWindow(m.monitorIdx, m.windowIdx, w)
+	}
+	return m, nil
+}
+
+// nextTool returns the enabled account after current, wrapping around.
+func (m AllModel) nextTool(current string) string {
+	accounts := config.EnabledAccounts(m.cfg.Accounts)
+	if len(accounts) == 0 {
+		return current
+	}
+	if current == "" {
+		current = "claude"
+	}
+	for i, a := range accounts {
+		if a.ID == current {
+			return accounts[(i+1)%len(accounts)].ID
+		}
+	}
+	return accounts[0].ID
+}
+
+// filteredProjects returns the projects matching the chooser's filter, best
+// match first.
+func (m AllModel) filteredProjects() []string {
+	results := fuzzyFilter(m.projectFilter, m.projects)
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.str
+	}
+	return out
+}
+
+// updateChooseProject handles the project chooser. Row 0 is "picker", which
+// clears the assignment; the remaining rows are the filtered projects.
+func (m AllModel) updateChooseProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filtered := m.filteredProjects()
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.choosing = false
+	case tea.KeyUp:
+		if m.projectIdx > 0 {
+			m.projectIdx--
+		}
+	case tea.KeyDown:
+		if m.projectIdx < len(filtered) {
+			m.projectIdx++
+		}
+	case tea.KeyEnter:
+		w := m.windowAt(m.monitorIdx, m.windowIdx)
+		w.Project = ""
+		if m.projectIdx > 0 {
+			w.Project = filtered[m.projectIdx-1]
+		}
+		m.setWindow(m.monitorIdx, m.windowIdx, w)
+		m.choosing = false
+	case tea.KeyBackspace:
+		if len(m.projectFilter) > 0 {
+			m.projectFilter = m.projectFilter[:len(m.projectFilter)-1]
+			m.projectIdx = 0
+		}
+	case tea.KeyRunes:
+		m.projectFilter += string(msg.Runes)
+		m.projectIdx = 0
+		if len(m.filteredProjects()) > 0 {
+			m.projectIdx = 1
+		}
 	}
 	return m, nil
 }
@@ -119,9 +283,92 @@ func (m AllModel) View() string {
 	if m.quitting || m.confirmed {
 		return ""
 	}
+	switch {
+	case m.choosing:
+		return m.viewChooseProject()
+	case m.assigning:
+		return m.viewAssign()
+	}
 	return m.viewMonitors()
 }
 
+// windowSummary describes a window's assignment, e.g. "api · codex".
+func windowSummary(w config.WindowConfig) string {
+	if w.Project == "" {
+		return "picker"
+	}
+	tool := w.Tool
+	if tool == "" {
+		tool = "claude"
+	}
+	return w.Project + " · " + tool
+}
+
+func (m AllModel) viewAssign() string {
+	var s strings.Builder
+
+	s.WriteString(RenderLogo("all"))
+	s.WriteString(RenderSep())
+	s.WriteString("\n")
+	s.WriteString("  " + SubtitleStyle.Render(fmt.Sprintf("Assign windows on Monitor %d", m.monitorIdx+1)) + "\n\n")
+
+	for i := 0; i < m.windowCounts[m.monitorIdx]; i++ {
+		w := m.windowAt(m.monitorIdx, i)
+		label := fmt.Sprintf("Window %d", i+1)
+		summary := windowSummary(w)
+		if i == m.windowIdx {
+			s.WriteString(fmt.Sprintf("  %s %s  %s\n",
+				TitleStyle.Render(">"),
+				SubtitleStyle.Render(label),
+				WhiteStyle.Render(summary)))
+		} else {
+			s.WriteString(fmt.Sprintf("    %s  %s\n",
+				DimStyle.Render(label),
+				DimStyle.Render(summary)))
+		}
+	}
+
+	s.WriteString("\n  " + DimStyle.Render("up/down select  Enter choose project  t cycle tool  x use picker  Esc back") + "\n")
+	return s.String()
+}
+
+func (m AllModel) viewChooseProject() string {
+	var s strings.Builder
+
+	s.WriteString(RenderLogo("all"))
+	s.WriteString(RenderSep())
+	s.WriteString("\n")
+	s.WriteString("  " + SubtitleStyle.Render(fmt.Sprintf("Project for Monitor %d, Window %d", m.monitorIdx+1, m.windowIdx+1)) + "\n\n")
+	s.WriteString("  " + DimStyle.Render("Filter:") + " " + WhiteStyle.Render(m.projectFilter) + "\n\n")
+
+	rows := append([]string{"(picker)"}, m.filteredProjects()...)
+
+	// Keep the cursor in a fixed-height window
+	visible := 12
+	if m.height > 0 && m.height-12 > 1 {
+		visible = m.height - 12
+	}
+	start := 0
+	if m.projectIdx >= visible {
+		start = m.projectIdx - visible + 1
+	}
+	end := start + visible
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	for i := start; i < end; i++ {
+		if i == m.projectIdx {
+			s.WriteString(fmt.Sprintf("  %s %s\n", TitleStyle.Render(">"), WhiteStyle.Render(rows[i])))
+		} else {
+			s.WriteString(fmt.Sprintf("    %s\n", DimStyle.Render(rows[i])))
+		}
+	}
+
+	s.WriteString("\n  " + DimStyle.Render("type to filter  up/down select  Enter assign  Esc back") + "\n")
+	return s.String()
+}
+
 func (m AllModel) viewMonitors() string {
 	var s strings.Builder
 
@@ -188,9 +435,16 @@ func (m AllModel) viewMonitors() string {
 		if selected && windows > 0 {
 			s.WriteString(renderLayoutPreview(windows))
 			s.WriteString("\n")
+			for j := 0; j < windows; j++ {
+				if w := m.windowAt(i, j); w.Project != "" {
+					s.WriteString(fmt.Sprintf("      %s %s\n",
+						DimStyle.Render(fmt.Sprintf("%d:", j+1)),
+						DimStyle.Render(windowSummary(w))))
+				}
+			}
 		}
 	}
 
-	s.WriteString("\n  " + DimStyle.Render("<-> select monitor  up/down adjust windows (0 = skip)  Enter launch  Esc quit") + "\n")
+	s.WriteString("\n  " + DimStyle.Render("<-> select monitor  up/down adjust windows (0 = skip)  a assign projects  Enter launch  Esc quit") + "\n")
 	return s.String()
 }
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bcmister/qs/internal/config"
@@ -64,3 +66,98 @@ func TestAllModel_totalWindows(t *testing.T) {
 		}
 	}
 }
+
+func TestAllModel_AssignProjectToWindow(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"api", "web"} {
+		if err := os.Mkdir(filepath.Join(root, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config.NewDefaultConfig(root)
+	m := NewAll(cfg)
+	m.monitors = make([]monitor.Monitor, 1)
+	m.windowCounts = []int{2}
+
+	press := func(msgs ...tea.KeyMsg) {
+		for _, msg := range msgs {
+			updated, _ := m.Update(msg)
+			m = updated.(AllModel)
+		}
+	}
+
+	// a → window 2 → Enter → filter "we" → Enter
+	press(
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")},
+		tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("we")},
+		tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")},
+	)
+
+	windows := m.Windows()
+	if len(windows) != 1 || len(windows[0]) != 2 {
+		t.Fatalf("expected 1 monitor with 2 windows, got %v", windows)
+	}
+	if windows[0][0].Project != "" {
+		t.Errorf("window 1 should still use the picker, got %q", windows[0][0].Project)
+	}
+	if windows[0][1].Project != "web" {
+		t.Errorf("expected window 2 assigned to web, got %q", windows[0][1].Project)
+	}
+	if windows[0][1].Tool == "" || windows[0][1].Tool == "claude" {
+		t.Errorf("expected t to cycle the tool away from claude, got %q", windows[0][1].Tool)
+	}
+
+	// Lowering and raising the count keeps the assignment
+	press(tea.KeyMsg{Type: tea.KeyEsc}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.Windows()[0][1].Project; got != "web" {
+		t.Errorf("assignment lost after count change, got %q", got)
+	}
+}
+
+func TestAllModel_AssignProjectFromOtherRoot(t *testing.T) {
+	root, work := t.TempDir(), t.TempDir()
+	if err := os.Mkdir(filepath.Join(work, "svc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewDefaultConfig(root)
+	if err := cfg.AddRoot("work", work); err != nil {
+		t.Fatal(err)
+	}
+	m := NewAll(cfg)
+	m.monitors = make([]monitor.Monitor, 1)
+	m.windowCounts = []int{1}
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("a")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("svc")},
+		{Type: tea.KeyEnter},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(AllModel)
+	}
+	if got := m.Windows()[0][0].Project; got != "work:svc" {
+		t.Errorf("expected window assigned to work:svc, got %q", got)
+	}
+}
+
+func TestAllModel_WindowsSeededFromConfig(t *testing.T) {
+	cfg := &config.Config{Monitors: []config.MonitorConfig{{
+		Layout:  "vertical",
+		Windows: []config.WindowConfig{{Tool: "codex", Project: "api"}, {Tool: "claude"}},
+	}}}
+	m := NewAll(cfg)
+	m.monitors = make([]monitor.Monitor, 1)
+	m.windowCounts = []int{3}
+
+	windows := m.Windows()[0]
+	if windows[0].Project != "api" || windows[0].Tool != "codex" {
+		t.Errorf("expected config assignment, got %+v", windows[0])
+	}
+	if windows[2].Project != "" {
+		t.Errorf("extra window should use the picker, got %+v", windows[2])
+	}
+}
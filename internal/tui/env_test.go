@@ -1,35 +1,71 @@
 package tui
 
 import (
+	"os/exec"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/bcmister/qs/internal/config"
 )
 
-func TestAccountEnvSlice(t *testing.T) {
+func TestApplyLaunchEnv(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-from-shell")
+	t.Setenv("OPENAI_API_KEY", "sk-openai-shell")
+	keys := config.AccountKeys{"claude": {"ANTHROPIC_API_KEY": "sk-test-123"}}
+	account := config.Account{ID: "claude", Env: config.EnvPolicy{Deny: []string{"OPENAI_*"}}}
+
+	c := exec.Command("true")
+	if err := applyLaunchEnv(c, nil, keys, account, ""); err != nil {
+		t.Fatal(err)
+	}
+	var anthropic []string
+	for _, kv := range c.Env {
+		if strings.HasPrefix(kv, "ANTHROPIC_API_KEY=") {
+			anthropic = append(anthropic, kv)
+		}
+	}
+	if !slices.Equal(anthropic, []string{"ANTHROPIC_API_KEY=sk-test-123"}) {
+		t.Errorf("ANTHROPIC_API_KEY entries = %v, want only the account's", anthropic)
+	}
+	if slices.Contains(c.Env, "OPENAI_API_KEY=sk-openai-shell") {
+		t.Error("OPENAI_API_KEY inherited despite the env policy")
+	}
+
+	// No keys and no policy: inherit the environment as-is
+	c = exec.Command("true")
+	if err := applyLaunchEnv(c, nil, nil, config.Account{ID: "claude"}, ""); err != nil || c.Env != nil {
+		t.Errorf("expected the inherited environment, got %v, %v", c.Env, err)
+	}
+}
+
+func TestApplyLaunchEnvResolvesReferences(t *testing.T) {
+	t.Setenv("QS_TEST_OPENAI_KEY", "sk-from-env")
 	keys := config.AccountKeys{
-		"claude": {"ANTHROPIC_API_KEY": "sk-test-123"},
-		"codex":  {"OPENAI_API_KEY": "sk-openai-456"},
+		"codex":  {"OPENAI_API_KEY": "env:QS_TEST_OPENAI_KEY"},
+		"gemini": {"GEMINI_API_KEY": "env:QS_TEST_UNSET_KEY"},
 	}
 
-	// Known account with keys
-	env := accountEnvSlice(keys, "claude")
-	if len(env) != 1 {
-		t.Fatalf("expected 1 env var for claude, got %d", len(env))
+	c := exec.Command("true")
+	if err := applyLaunchEnv(c, nil, keys, config.Account{ID: "codex"}, ""); err != nil {
+		t.Fatal(err)
 	}
-	if env[0] != "ANTHROPIC_API_KEY=sk-test-123" {
-		t.Errorf("expected ANTHROPIC_API_KEY=sk-test-123, got %s", env[0])
+	if !slices.Contains(c.Env, "OPENAI_API_KEY=sk-from-env") {
+		t.Errorf("reference not resolved: %v", c.Env)
 	}
 
-	// Account with no keys
-	env = accountEnvSlice(keys, "gemini")
-	if len(env) != 0 {
-		t.Errorf("expected 0 env vars for gemini, got %d", len(env))
+	c = exec.Command("true")
+	if err := applyLaunchEnv(c, nil, keys, config.Account{ID: "gemini"}, ""); err == nil {
+		t.Error("expected an error for an unset env: reference")
 	}
+}
+
+func TestProbeAuthCmdResolvesInCommand(t *testing.T) {
+	keys := config.AccountKeys{"gemini": {"GEMINI_API_KEY": "env:QS_TEST_UNSET_KEY"}}
 
-	// Empty keys map
-	env = accountEnvSlice(nil, "claude")
-	if len(env) != 0 {
-		t.Errorf("expected 0 env vars with nil keys, got %d", len(env))
+	cmd := probeAuthCmd(nil, keys, config.Account{ID: "gemini", Command: "gemini"})
+	msg, ok := cmd().(authProbeMsg)
+	if !ok || msg.accountID != "gemini" || msg.err == nil {
+		t.Errorf("expected the unresolved reference in authProbeMsg, got %+v", msg)
 	}
 }
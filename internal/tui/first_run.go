@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/bcmister/qs/internal/config"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -29,15 +28,15 @@ const (
 type FirstRunResult struct {
 	Action       FirstRunAction
 	ProjectsRoot string
+	Roots        []config.ProjectRoot // all roots entered; the first is ProjectsRoot
 }
 
 // FirstRunModel is the first-run choice + quick path input flow.
 type FirstRunModel struct {
-	step      firstRunStep
-	choice    int
-	rootInput textinput.Model
-	errText   string
-	result    FirstRunResult
+	step   firstRunStep
+	choice int
+	roots  rootsEditor
+	result FirstRunResult
 }
 
 // NewFirstRun creates a first-run flow model.
@@ -47,16 +46,10 @@ func NewFirstRun(existing *config.Config) FirstRunModel {
 		defaultRoot = strings.TrimSpace(existing.ProjectsRoot)
 	}
 
-	input := textinput.New()
-	input.Placeholder = config.DefaultProjectsRoot()
-	input.Width = 56
-	input.CharLimit = 512
-	input.SetValue(defaultRoot)
-
 	return FirstRunModel{
-		step:      firstRunChoice,
-		choice:    0,
-		rootInput: input,
+		step:   firstRunChoice,
+		choice: 0,
+		roots:  newRootsEditor(nil, defaultRoot),
 	}
 }
 
@@ -96,8 +89,7 @@ func (m FirstRunModel) updateChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 		m.step = firstRunPathInput
-		m.rootInput.Focus()
-		return m, textinput.Blink
+		return m, m.roots.input.Focus()
 	}
 	return m, nil
 }
@@ -109,22 +101,21 @@ func (m FirstRunModel) updatePathInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	case tea.KeyEsc:
 		m.step = firstRunChoice
-		m.errText = ""
-		m.rootInput.Blur()
+		m.roots.err = ""
+		m.roots.input.Blur()
 		return m, nil
 	case tea.KeyEnter:
-		root := strings.TrimSpace(m.rootInput.Value())
-		if root == "" {
-			m.errText = "Project path is required."
+		roots, ok := m.roots.finish()
+		if !ok {
 			return m, nil
 		}
 		m.result.Action = FirstRunSetPathNow
-		m.result.ProjectsRoot = root
+		m.result.ProjectsRoot = roots[0].Path
+		m.result.Roots = roots
 		return m, tea.Quit
 	default:
 		var cmd tea.Cmd
-		m.rootInput, cmd = m.rootInput.Update(msg)
-		m.errText = ""
+		m.roots, cmd = m.roots.update(msg)
 		return m, cmd
 	}
 }
@@ -153,12 +144,10 @@ func (m FirstRunModel) View() string {
 	}
 
 	s.WriteString("  " + TitleStyle.Render("Set project path") + "\n\n")
-	s.WriteString("  " + DimStyle.Render("This is the folder qs will open by default.") + "\n\n")
-	s.WriteString("  " + m.rootInput.View() + "\n")
-	if m.errText != "" {
-		s.WriteString("\n  " + ErrorStyle.Render(m.errText) + "\n")
-	}
-	s.WriteString("\n  " + DimStyle.Render("enter save  esc back") + "\n")
+	s.WriteString("  " + DimStyle.Render("This is the folder qs will open by default.") + "\n")
+	s.WriteString("  " + DimStyle.Render("Press tab to add it and enter more folders; the picker merges them all.") + "\n\n")
+	s.WriteString(m.roots.view())
+	s.WriteString("\n  " + DimStyle.Render("enter save  tab add another  ctrl+x remove  esc back") + "\n")
 	return s.String()
 }
 
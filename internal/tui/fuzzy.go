@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fuzzy match scoring. A match is a subsequence of the candidate; each matched
+// character scores fuzzyMatchScore plus a bonus for where it lands, and gaps
+// between matched characters are penalised. The weights follow the same idea
+// as fzf: boundaries beat camelCase humps, which beat mid-word matches, and
+// consecutive characters inherit the bonus of the start of their run.
+const (
+	fuzzyMatchScore     = 16
+	fuzzyGapStart       = -3
+	fuzzyGapExtension   = -1
+	fuzzyBonusBoundary  = 8 // start of string or after - _ . / \ or space
+	fuzzyBonusCamel     = 7 // lower→Upper or letter→digit transition
+	fuzzyBonusFirstChar = 2 // extra weight for the first pattern character
+	fuzzyBonusRun       = 4 // minimum bonus for a consecutive match
+	fuzzyNoMatch        = -1 << 30
+)
+
+// fuzzyResult is one candidate that matched the query.
+type fuzzyResult struct {
+	index     int // position in the items passed to fuzzyFilter
+	str       string
+	score     int
+	positions []int // matched rune indices, ascending
+}
+
+// fuzzyMatcher scores candidates against one query, reusing its buffers so
+// filtering thousands of entries allocates only for the results.
+type fuzzyMatcher struct {
+	terms [][]rune
+
+	runes   []rune
+	lower   []rune
+	bonus   []int
+	score   []int // len(term) × len(candidate) DP table, row-major
+	run     []int // consecutive-run bonus carried at each cell
+	from    []int // backtracking: previous matched index, -1 for none
+	matched []int
+}
+
+// newFuzzyMatcher returns a matcher for query. Whitespace separates terms
+// that must all match, in any order.
+func newFuzzyMatcher(query string) *fuzzyMatcher {
+	m := &fuzzyMatcher{}
+	for _, t := range strings.Fields(query) {
+		m.terms = append(m.terms, []rune(strings.ToLower(t)))
+	}
+	return m
+}
+
+// match scores str and returns the matched rune positions. ok is false if
+// any term is not a subsequence of str.
+func (m *fuzzyMatcher) match(str string) (score int, positions []int, ok bool) {
+	m.runes = m.runes[:0]
+	m.lower = m.lower[:0]
+	for _, r := range str {
+		m.runes = append(m.runes, r)
+		m.lower = append(m.lower, unicode.ToLower(r))
+	}
+	m.computeBonuses()
+
+	m.matched = m.matched[:0]
+	for _, term := range m.terms {
+		s, ok := m.matchTerm(term)
+		if !ok {
+			return 0, nil, false
+		}
+		score += s
+	}
+
+	positions = append([]int(nil), m.matched...)
+	sort.Ints(positions)
+	return score, dedupSorted(positions), true
+}
+
+func (m *fuzzyMatcher) computeBonuses() {
+	m.bonus = m.bonus[:0]
+	prev := rune(0)
+	for i, r := range m.runes {
+		b := 0
+		switch {
+		case i == 0 || isFuzzySeparator(prev):
+			b = fuzzyBonusBoundary
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			b = fuzzyBonusCamel
+		case unicode.IsDigit(r) && unicode.IsLetter(prev):
+			b = fuzzyBonusCamel
+		}
+		m.bonus = append(m.bonus, b)
+		prev = r
+	}
+}
+
+func isFuzzySeparator(r rune) bool {
+	switch r {
+	case '-', '_', '.', '/', '\\', ' ':
+		return true
+	}
+	return false
+}
+
+// matchTerm finds the best-scoring alignment of term in the current
+// candidate and appends its positions to m.matched.
+func (m *fuzzyMatcher) matchTerm(term []rune) (int, bool) {
+	n, w := len(m.lower), len(term)
+	if w == 0 {
+		return 0, true
+	}
+	if w > n || !isSubsequence(term, m.lower) {
+		return 0, false
+	}
+
+	size := w * n
+	m.score = grow(m.score, size)
+	m.run = grow(m.run, size)
+	m.from = grow(m.from, size)
+
+	for i := 0; i < w; i++ {
+		row := i * n
+		prevRow := (i - 1) * n
+
+		// best holds the best score of the previous row ending before j-1,
+		// already charged for the gap up to j.
+		best, bestFrom := fuzzyNoMatch, -1
+
+		for j := 0; j < n; j++ {
+			cell := row + j
+			m.score[cell] = fuzzyNoMatch
+			m.from[cell] = -1
+			m.run[cell] = 0
+
+			if i > 0 && j >= 2 {
+				if p := m.score[prevRow+j-2]; p > fuzzyNoMatch && p+fuzzyGapStart > best+fuzzyGapExtension {
+					best, bestFrom = p+fuzzyGapStart, j-2
+				} else if best > fuzzyNoMatch {
+					best += fuzzyGapExtension
+				}
+			}
+
+			if m.lower[j] != term[i] {
+				continue
+			}
+
+			bonus := m.bonus[j]
+			if i == 0 {
+				m.score[cell] = fuzzyMatchScore + bonus*fuzzyBonusFirstChar
+				m.run[cell] = bonus
+				continue
+			}
+			if j == 0 {
+				continue
+			}
+
+			// Consecutive: extend the run from the diagonal
+			if p := m.score[prevRow+j-1]; p > fuzzyNoMatch {
+				runBonus := m.run[prevRow+j-1]
+				if runBonus < fuzzyBonusRun {
+					runBonus = fuzzyBonusRun
+				}
+				if bonus > runBonus {
+					runBonus = bonus
+				}
+				m.score[cell] = p + fuzzyMatchScore + runBonus
+				m.run[cell] = runBonus
+				m.from[cell] = j - 1
+			}
+			// Gap: jump from the best earlier match
+			if best > fuzzyNoMatch {
+				if s := best + fuzzyMatchScore + bonus; s > m.score[cell] {
+					m.score[cell] = s
+					m.run[cell] = bonus
+					m.from[cell] = bestFrom
+				}
+			}
+		}
+	}
+
+	// Best end position in the last row
+	last := (w - 1) * n
+	end, endScore := -1, fuzzyNoMatch
+	for j := 0; j < n; j++ {
+		if s := m.score[last+j]; s > endScore {
+			end, endScore = j, s
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+
+	for i, j := w-1, end; i >= 0 && j >= 0; i-- {
+		m.matched = append(m.matched, j)
+		j = m.from[i*n+j]
+	}
+	return endScore, true
+}
+
+func isSubsequence(term, s []rune) bool {
+	i := 0
+	for _, r := range s {
+		if i < len(term) && r == term[i] {
+			i++
+		}
+	}
+	return i == len(term)
+}
+
+func grow(buf []int, n int) []int {
+	if cap(buf) < n {
+		return make([]int, n)
+	}
+	return buf[:n]
+}
+
+func dedupSorted(xs []int) []int {
+	if len(xs) < 2 {
+		return xs
+	}
+	out := xs[:1]
+	for _, x := range xs[1:] {
+		if x != out[len(out)-1] {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// fuzzyFilter returns the items matching query, best match first. Ties go
+// to the shorter item, then alphabetical order. An empty query returns items
+// unchanged with no highlights.
+func fuzzyFilter(query string, items []string) []fuzzyResult {
+	if strings.TrimSpace(query) == "" {
+		results := make([]fuzzyResult, len(items))
+		for i, s := range items {
+			results[i] = fuzzyResult{index: i, str: s}
+		}
+		return results
+	}
+
+	m := newFuzzyMatcher(query)
+	var results []fuzzyResult
+	for i, s := range items {
+		if score, pos, ok := m.match(s); ok {
+			results = append(results, fuzzyResult{index: i, str: s, score: score, positions: pos})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if la, lb := utf8.RuneCountInString(a.str), utf8.RuneCountInString(b.str); la != lb {
+			return la < lb
+		}
+		return a.str < b.str
+	})
+	return results
+}
+
+// highlightMatches renders s with the runes at positions in hl and the rest
+// in base.
+func highlightMatches(s string, positions []int, base, hl lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(s)
+	}
+
+	var b strings.Builder
+	var chunk []rune
+	inMatch := false
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString(hl.Render(string(chunk)))
+		} else {
+			b.WriteString(base.Render(string(chunk)))
+		}
+		chunk = chunk[:0]
+	}
+
+	p := 0
+	for i, r := range []rune(s) {
+		isMatch := p < len(positions) && positions[p] == i
+		if isMatch {
+			p++
+		}
+		if isMatch != inMatch {
+			flush()
+			inMatch = isMatch
+		}
+		chunk = append(chunk, r)
+	}
+	flush()
+	return b.String()
+}
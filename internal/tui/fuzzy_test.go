@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFuzzyMatchSubsequence(t *testing.T) {
+	m := newFuzzyMatcher("qsrv")
+	if _, pos, ok := m.match("quick-server"); !ok {
+		t.Fatal("expected qsrv to match quick-server")
+	} else if want := []int{0, 6, 8, 9}; !reflect.DeepEqual(pos, want) {
+		t.Errorf("positions = %v, want %v", pos, want)
+	}
+	if _, _, ok := m.match("quick-service-x"); !ok {
+		t.Error("expected qsrv to match quick-service-x")
+	}
+	if _, _, ok := m.match("server-quick"); ok {
+		t.Error("qsrv should not match server-quick (out of order)")
+	}
+}
+
+func TestFuzzyMatchCaseInsensitive(t *testing.T) {
+	if _, _, ok := newFuzzyMatcher("MYAPP").match("my-app"); !ok {
+		t.Error("expected case-insensitive match")
+	}
+}
+
+func TestFuzzyMatchMultipleTerms(t *testing.T) {
+	m := newFuzzyMatcher("srv quick")
+	_, pos, ok := m.match("quick-server")
+	if !ok {
+		t.Fatal("expected all terms to match in any order")
+	}
+	if len(pos) != 8 {
+		t.Errorf("expected union of 8 positions, got %v", pos)
+	}
+	if _, _, ok := m.match("server"); ok {
+		t.Error("expected no match when one term is missing")
+	}
+}
+
+func TestFuzzyRanking(t *testing.T) {
+	tests := []struct {
+		query string
+		items []string
+		best  string
+	}{
+		// word boundaries beat scattered mid-word matches
+		{"qs", []string{"requests", "quick-start"}, "quick-start"},
+		// camelCase humps beat mid-word matches
+		{"fb", []string{"afoobar", "fooBar"}, "fooBar"},
+		// consecutive runs beat gaps
+		{"api", []string{"a-p-i-gateway", "api-gateway"}, "api-gateway"},
+		// exact prefix beats a later match
+		{"web", []string{"my-web", "web"}, "web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := fuzzyFilter(tt.query, tt.items)
+			if len(results) == 0 {
+				t.Fatalf("no matches for %q", tt.query)
+			}
+			if results[0].str != tt.best {
+				t.Errorf("best match for %q = %q, want %q (results %+v)", tt.query, results[0].str, tt.best, results)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilterEmptyQuery(t *testing.T) {
+	items := []string{"b", "a"}
+	results := fuzzyFilter("  ", items)
+	if len(results) != 2 || results[0].str != "b" || results[0].positions != nil {
+		t.Errorf("empty query should return items unchanged, got %+v", results)
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	base := lipgloss.NewStyle()
+	hl := lipgloss.NewStyle()
+	if got := highlightMatches("quick-server", []int{0, 6}, base, hl); got != "quick-server" {
+		t.Errorf("highlight changed text: %q", got)
+	}
+}
+
+func BenchmarkFuzzyFilter(b *testing.B) {
+	items := make([]string, 5000)
+	for i := range items {
+		items[i] = fmt.Sprintf("project-%04d-serviceName-%d", i, i%7)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fuzzyFilter("prsvn", items)
+	}
+}
@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bcmister/qs/internal/worktree"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxBranchWidth truncates long branch names in the picker's status column.
+const maxBranchWidth = 24
+
+// gitStatusSem caps how many git processes the picker runs at once, so a
+// status filter over thousands of projects doesn't fork them all together.
+var gitStatusSem = make(chan struct{}, 8)
+
+// gitStatusEntry is the cached status of one project directory. ok is false
+// for directories that aren't repositories.
+type gitStatusEntry struct {
+	status worktree.Status
+	ok     bool
+}
+
+// gitStatusMsg delivers the status of one project directory.
+type gitStatusMsg struct {
+	dir   string
+	entry gitStatusEntry
+}
+
+// fetchGitStatus reads dir's git status off the UI goroutine.
+func fetchGitStatus(dir string) tea.Cmd {
+	return func() tea.Msg {
+		gitStatusSem <- struct{}{}
+		defer func() { <-gitStatusSem }()
+		st, err := worktree.StatusOf(dir)
+		return gitStatusMsg{dir: dir, entry: gitStatusEntry{status: st, ok: err == nil}}
+	}
+}
+
+// statusStates are the values is: accepts. A prefix of one is enough, so
+// the list narrows while the token is still being typed.
+var statusStates = []string{"dirty", "clean", "ahead", "behind"}
+
+// statusFilter holds the is: and branch: tokens of a picker filter.
+type statusFilter struct {
+	states   []string
+	branches []string
+}
+
+// parseStatusFilter splits the filter into the fuzzy query and its status
+// tokens. Unknown is: values match nothing; empty tokens are ignored.
+func parseStatusFilter(filter string) (string, statusFilter) {
+	var query []string
+	var f statusFilter
+	for _, tok := range strings.Fields(filter) {
+		lower := strings.ToLower(tok)
+		switch {
+		case strings.HasPrefix(lower, "is:"):
+			v := strings.TrimPrefix(lower, "is:")
+			if v == "" {
+				continue
+			}
+			state := "unknown"
+			for _, s := range statusStates {
+				if strings.HasPrefix(s, v) {
+					state = s
+					break
+				}
+			}
+			f.states = append(f.states, state)
+		case strings.HasPrefix(lower, "branch:"):
+			if v := strings.TrimPrefix(lower, "branch:"); v != "" {
+				f.branches = append(f.branches, v)
+			}
+		default:
+			query = append(query, tok)
+		}
+	}
+	return strings.Join(query, " "), f
+}
+
+// active reports whether the filter needs git status to match.
+func (f statusFilter) active() bool {
+	return len(f.states) > 0 || len(f.branches) > 0
+}
+
+// match reports whether a repository's status satisfies every token.
+func (f statusFilter) match(st worktree.Status) bool {
+	for _, s := range f.states {
+		switch {
+		case s == "dirty" && st.Dirty:
+		case s == "clean" && !st.Dirty:
+		case s == "ahead" && st.Ahead > 0:
+		case s == "behind" && st.Behind > 0:
+		default:
+			return false
+		}
+	}
+	for _, b := range f.branches {
+		if !strings.Contains(strings.ToLower(st.Branch), b) {
+			return false
+		}
+	}
+	return true
+}
+
+// statusMatches reports whether dir passes the filter's status tokens.
+// Directories whose status hasn't arrived yet are left out until it does.
+func (m PickerModel) statusMatches(f statusFilter, dir string) bool {
+	if !f.active() {
+		return true
+	}
+	e, known := m.gitStatus[dir]
+	return known && e.ok && f.match(e.status)
+}
+
+// loadGitStatus starts status reads for the project rows on screen, or for
+// every project while the filter has status tokens. Each directory is read
+// once per picker.
+func (m PickerModel) loadGitStatus() tea.Cmd {
+	if m.stage != stageProject {
+		return nil
+	}
+
+	var dirs []string
+	if _, f := parseStatusFilter(m.filter); f.active() {
+		dirs = append(dirs, m.projectDirs...)
+		for _, ref := range m.existingFavorites() {
+			dirs = append(dirs, m.cfg.RefDir(ref))
+		}
+	} else {
+		start, end := m.visibleRange()
+		dirs = m.filteredDirs[start:end]
+	}
+
+	var cmds []tea.Cmd
+	for _, dir := range dirs {
+		if _, done := m.gitStatus[dir]; done || m.gitPending[dir] {
+			continue
+		}
+		m.gitPending[dir] = true
+		cmds = append(cmds, fetchGitStatus(dir))
+	}
+	return tea.Batch(cmds...)
+}
+
+// visibleRange returns the filtered projects shown in the list.
+func (m PickerModel) visibleRange() (int, int) {
+	maxShow := m.maxVisible() - 1
+	if maxShow < 1 {
+		maxShow = 1
+	}
+	start := m.viewOffset
+	if maxOff := len(m.filtered) - maxShow; start > maxOff {
+		start = maxOff
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxShow
+	if end > len(m.filtered) {
+		end = len(m.filtered)
+	}
+	return start, end
+}
+
+// truncateBranch shortens a branch name to maxBranchWidth runes.
+func truncateBranch(branch string) string {
+	if utf8.RuneCountInString(branch) <= maxBranchWidth {
+		return branch
+	}
+	return string([]rune(branch)[:maxBranchWidth-1]) + "…"
+}
+
+// renderGitStatus renders a project row's status column: a dirty/clean
+// marker, the branch padded to branchWidth, the age of the last commit and
+// the ahead/behind counts versus upstream.
+func renderGitStatus(st worktree.Status, branchWidth int, dim lipgloss.Style) string {
+	marker := lipgloss.NewStyle().Foreground(ColorGreen).Render("✓")
+	if st.Dirty {
+		marker = lipgloss.NewStyle().Foreground(ColorYellow).Render("*")
+	}
+
+	branch := truncateBranch(st.Branch)
+	branch += strings.Repeat(" ", branchWidth-lipgloss.Width(branch))
+
+	age := ""
+	if !st.LastCommit.IsZero() {
+		age = FormatAge(time.Since(st.LastCommit))
+	}
+
+	var ab []string
+	if st.Ahead > 0 {
+		ab = append(ab, fmt.Sprintf("↑%d", st.Ahead))
+	}
+	if st.Behind > 0 {
+		ab = append(ab, fmt.Sprintf("↓%d", st.Behind))
+	}
+
+	col := marker + " " + dim.Render(branch)
+	if len(ab) == 0 {
+		return col + "  " + dim.Render(age)
+	}
+	col += "  " + dim.Render(fmt.Sprintf("%-8s", age))
+	return col + "  " + lipgloss.NewStyle().Foreground(ColorBrCyan).Render(strings.Join(ab, " "))
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcmister/qs/internal/worktree"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseStatusFilter(t *testing.T) {
+	query, f := parseStatusFilter("api is:dirty Branch:Feat web")
+	if query != "api web" {
+		t.Errorf("expected status tokens removed from the query, got %q", query)
+	}
+	if strings.Join(f.states, ",") != "dirty" || strings.Join(f.branches, ",") != "feat" {
+		t.Errorf("unexpected tokens %+v", f)
+	}
+
+	if _, f := parseStatusFilter("is: branch:"); f.active() {
+		t.Errorf("expected empty tokens ignored, got %+v", f)
+	}
+	if _, f := parseStatusFilter("is:cl"); strings.Join(f.states, ",") != "clean" {
+		t.Errorf("expected is:cl to mean clean, got %+v", f)
+	}
+
+	dirty := worktree.Status{Branch: "feat/login", Dirty: true, Ahead: 1}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"is:dirty", true},
+		{"is:clean", false},
+		{"is:ahead", true},
+		{"is:behind", false},
+		{"is:bogus", false},
+		{"branch:login", true},
+		{"branch:main", false},
+		{"is:dirty branch:feat", true},
+	}
+	for _, tt := range tests {
+		if _, f := parseStatusFilter(tt.filter); f.match(dirty) != tt.want {
+			t.Errorf("%q: match = %v, want %v", tt.filter, !tt.want, tt.want)
+		}
+	}
+}
+
+func TestPickerGitStatusColumnsAndFilter(t *testing.T) {
+	root, cfg := setupTestDirs(t)
+	alpha := filepath.Join(root, "alpha")
+	beta := filepath.Join(root, "beta")
+
+	m := NewPicker(cfg, nil)
+	if m.loadGitStatus() == nil || !m.gitPending[alpha] || !m.gitPending[beta] {
+		t.Fatalf("expected status reads for the visible rows, pending=%v", m.gitPending)
+	}
+	if m.loadGitStatus() != nil {
+		t.Error("expected no second read while one is pending")
+	}
+
+	deliver := func(m PickerModel, dir string, st worktree.Status) PickerModel {
+		updated, _ := m.Update(gitStatusMsg{dir: dir, entry: gitStatusEntry{status: st, ok: true}})
+		return updated.(PickerModel)
+	}
+	m = deliver(m, alpha, worktree.Status{Branch: "feat/login", Dirty: true, Ahead: 2, LastCommit: time.Now().Add(-3 * time.Hour)})
+
+	view := m.View()
+	for _, want := range []string{"feat/login", "↑2", "3h ago"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the alpha row:\n%s", want, view)
+		}
+	}
+
+	// beta's status hasn't arrived, so is:clean can't match it yet
+	m = typeFilter(m, "is:clean")
+	if len(m.filtered) != 0 {
+		t.Fatalf("expected no clean projects yet, got %v", m.filtered)
+	}
+	if !strings.Contains(m.View(), "reading git status") {
+		t.Error("expected a loading hint while statuses are pending")
+	}
+	m = deliver(m, beta, worktree.Status{Branch: "main"})
+	if strings.Join(m.filtered, ",") != "beta" {
+		t.Errorf("expected beta once its status is clean, got %v", m.filtered)
+	}
+
+	m = typeFilter(NewPicker(cfg, nil), "al branch:feat")
+	m = deliver(m, alpha, worktree.Status{Branch: "feat/login"})
+	if strings.Join(m.filtered, ",") != "alpha" {
+		t.Errorf("expected alpha for branch:feat, got %v", m.filtered)
+	}
+}
+
+func typeFilter(m PickerModel, filter string) PickerModel {
+	for _, r := range filter {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(PickerModel)
+	}
+	return m
+}
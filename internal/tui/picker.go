@@ -7,9 +7,14 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/discover"
+	"github.com/bcmister/qs/internal/scaffold"
+	"github.com/bcmister/qs/internal/session"
+	"github.com/bcmister/qs/internal/worktree"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -21,6 +26,8 @@ const (
 	stageProject pickerStage = iota
 	stageCreate
 	stageAccount
+	stagePrompt
+	stageResume
 )
 
 var windowsReservedNames = map[string]struct{}{
@@ -29,9 +36,13 @@ var windowsReservedNames = map[string]struct{}{
 	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
 }
 
+// maxRecentProjects is how many history entries the Recent section shows.
+const maxRecentProjects = 5
+
 // browseEntry stores state for one level of directory navigation.
 type browseEntry struct {
 	dir        string
+	root       string
 	cursor     int
 	viewOffset int
 	filter     string
@@ -49,39 +60,79 @@ type PickerModel struct {
 
 	// Project stage
 	projects           []string
+	projectDirs        []string // absolute path of each project
+	projectRoots       []string // root name of each project
 	filtered           []string
+	filteredDirs       []string
+	filteredRoots      []string
+	highlights         [][]int // matched rune positions per filtered entry
+	rootFilter         string  // top level lists only this root when set; tab cycles
 	filter             string
-	cursor             int // 0 is create-new-folder, 1..n are projects
+	cursor             int // negative values are pinned then recent rows, 0 is create-new-folder, 1..n are projects
 	viewOffset         int
 	statusMsg          string
 	statusErr          bool
 	preselectedProject string
+	preselectedDir     string
+	pathOnly           bool                   // choosing a project quits with its path instead of launching (qs pick)
+	pickedDir          string                 // the chosen project in path-only mode
+	pinned             []fuzzyResult          // favorites matching the filter; str is the project reference
+	recent             []config.RecentProject // frecent launch dirs, shown unfiltered at the root
+
+	// Discovery mode lists indexed projects at the top level instead of
+	// the roots' direct children
+	index      *discover.Index // owned by the background refresh once started
+	discovered []discover.Project
+	indexing   bool // a background refresh is running
+
+	// Git status columns, filled in asynchronously per directory
+	gitStatus  map[string]gitStatusEntry
+	gitPending map[string]bool
+
+	// Preview pane beside the list on wide terminals, loaded lazily
+	showPreview    bool
+	previews       map[string]projectPreview
+	previewPending map[string]bool
 
 	// Directory browsing
 	browseDir   string
+	browseRoot  string // root the browse directory belongs to, "" at the top
 	browseStack []browseEntry
 	launchDir   string
 
 	// Create folder stage
 	createInput string
 	createErr   string
+	templateIdx int  // 0 is an empty folder, i is cfg.Templates[i-1]
+	creating    bool // a template is being applied in the background
 
 	// Account stage
-	selected   string
-	accounts   []config.Account
-	accountIdx int
+	selected    string
+	accounts    []config.Account
+	accountIdx  int
+	canWorktree bool // launchDir is inside a git repository
+	useWorktree bool // launch in a fresh git worktree instead of launchDir
+	preparing   string // what a launch is waiting for, e.g. "creating worktree"
+	accountErr  string
+	projectEnv  []config.EnvVar // launchDir's project env, shown with each account's keys
+
+	// Prompt stage: an initial task passed to the tool at launch
+	prompt      string
+	promptInput string
+
+	// Resume stage: the selected account's stored sessions in launchDir
+	sessions       []session.Session
+	sessionIdx     int
+	sessionsLoaded bool
+	sessionErr     string
 }
 
-// NewPicker creates a new picker model.
-func NewPicker(cfg *config.Config) PickerModel {
-	projects := scanProjects(cfg.ProjectsRoot)
+// NewPicker creates a new picker model. keys are the loaded keys file,
+// which the caller reads before the TUI starts, as it may need to ask for
+// a passphrase.
+func NewPicker(cfg *config.Config, keys config.AccountKeys) PickerModel {
 	accounts := config.EnabledAccounts(cfg.Accounts)
-	keys, _ := config.LoadKeys()
-
-	cursor := 0
-	if len(projects) > 0 {
-		cursor = 1
-	}
+	recent, historyErr := loadRecent(cfg)
 
 	accountIdx := 0
 	preselect := cfg.LastAccount
@@ -97,35 +148,120 @@ func NewPicker(cfg *config.Config) PickerModel {
 		}
 	}
 
-	return PickerModel{
+	m := PickerModel{
 		cfg:        cfg,
 		keys:       keys,
 		stage:      stageProject,
-		projects:   projects,
-		filtered:   projects,
-		cursor:     cursor,
 		accounts:   accounts,
 		accountIdx: accountIdx,
 		browseDir:  cfg.ProjectsRoot,
+		recent:     recent,
+		gitStatus:  make(map[string]gitStatusEntry),
+		gitPending: make(map[string]bool),
+
+		showPreview:    true,
+		previews:       make(map[string]projectPreview),
+		previewPending: make(map[string]bool),
+	}
+	if historyErr != nil {
+		m.statusMsg = fmt.Sprintf("%v (fix %s or run qs history clear)", historyErr, config.HistoryPath())
+		m.statusErr = true
+	}
+	if cfg.Discovery.Enabled {
+		m.index, _ = discover.LoadIndex("")
+		if m.index == nil {
+			m.index = &discover.Index{}
+		}
+		m.discovered, _ = m.index.Cached(discoverRoots(cfg))
+		m.indexing = true
 	}
+	m.refreshProjects()
+	return m
 }
 
 // NewPickerWithProject creates a picker that skips straight to account selection
-// for the given project name.
-func NewPickerWithProject(cfg *config.Config, project string) PickerModel {
-	m := NewPicker(cfg)
+// for the given project, resolved with config.ResolveProject: a folder in any
+// root, root:name, or a favorite's short name.
+func NewPickerWithProject(cfg *config.Config, keys config.AccountKeys, project string) PickerModel {
+	m := NewPicker(cfg, keys)
 	m.preselectedProject = project
+	if dir, err := cfg.ResolveProject(project); err == nil {
+		m.preselectedProject = cfg.ProjectRef(dir)
+		m.preselectedDir = dir
+	}
+	return m
+}
+
+// NewPickerInDir creates a picker that skips straight to account selection
+// for an arbitrary directory, such as a worktree outside the projects root.
+func NewPickerInDir(cfg *config.Config, keys config.AccountKeys, dir string) PickerModel {
+	m := NewPicker(cfg, keys)
+	m.preselectedProject = filepath.Base(dir)
+	m.preselectedDir = dir
+	return m
+}
+
+// WithPrompt sets an initial prompt passed to the tool at launch, as with
+// the --prompt flag.
+func (m PickerModel) WithPrompt(prompt string) PickerModel {
+	m.prompt = strings.TrimSpace(prompt)
+	return m
+}
+
+// WithPathOnly makes the picker stop at the project stage: choosing a
+// project quits with its directory in PickedDir instead of launching a tool.
+func (m PickerModel) WithPathOnly() PickerModel {
+	m.pathOnly = true
+	return m
+}
+
+// WithFilter starts the picker with the project filter already typed.
+func (m PickerModel) WithFilter(filter string) PickerModel {
+	m.filter = filter
+	m.applyFilter()
 	return m
 }
 
+// PickedDir returns the directory chosen in path-only mode, or "" if the
+// picker was cancelled.
+func (m PickerModel) PickedDir() string {
+	return m.pickedDir
+}
+
+// scaffoldDoneMsg is sent when a template has been applied to a new
+// project folder.
+type scaffoldDoneMsg struct {
+	name string
+	dir  string
+	err  error
+}
+
+// launchReadyMsg is sent when a launch's environment has been resolved
+// and, if it runs in a worktree, the worktree has been created.
+type launchReadyMsg struct {
+	cmd       *exec.Cmd
+	accountID string
+	dir       string // launchDir, or its counterpart inside the worktree
+	err       error
+}
+
 // preselectedProjectMsg is sent when a project was pre-selected via --project flag.
 type preselectedProjectMsg struct{}
 
+// indexRefreshedMsg is sent when the background index refresh finishes.
+type indexRefreshedMsg struct {
+	projects []discover.Project
+	err      error
+}
+
 func (m PickerModel) Init() tea.Cmd {
 	if m.preselectedProject != "" {
 		return func() tea.Msg { return preselectedProjectMsg{} }
 	}
-	return nil
+	if m.indexing {
+		return tea.Batch(refreshIndex(m.index, m.cfg), m.loadGitStatus())
+	}
+	return m.loadGitStatus()
 }
 
 func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -133,20 +269,74 @@ func (m PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		return m, nil
+		return m, tea.Batch(m.loadGitStatus(), m.loadPreview())
 	case tea.KeyMsg:
 		switch m.stage {
 		case stageProject:
 			return m.updateProject(msg)
 		case stageCreate:
 			return m.updateCreate(msg)
+		case stagePrompt:
+			return m.updatePrompt(msg)
+		case stageResume:
+			return m.updateResume(msg)
 		default:
 			return m.updateAccount(msg)
 		}
 	case preselectedProjectMsg:
 		m.selected = m.preselectedProject
-		m.launchDir = filepath.Join(m.cfg.ProjectsRoot, m.preselectedProject)
+		m.launchDir = m.cfg.RefDir(m.preselectedProject)
+		if m.preselectedDir != "" {
+			m.launchDir = m.preselectedDir
+		}
 		return m.startAccountSelection()
+	case indexRefreshedMsg:
+		m.indexing = false
+		m.discovered = msg.projects
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Failed to save project index: %v", msg.err)
+			m.statusErr = true
+		}
+		if m.stage == stageProject && m.atTop() {
+			m.keepCursor(m.refreshProjects)
+		}
+		return m, tea.Batch(m.loadGitStatus(), m.loadPreview())
+	case gitStatusMsg:
+		delete(m.gitPending, msg.dir)
+		m.gitStatus[msg.dir] = msg.entry
+		if _, f := parseStatusFilter(m.filter); f.active() && m.stage == stageProject {
+			m.keepCursor(m.applyFilter)
+			return m, m.loadPreview()
+		}
+		return m, nil
+	case scaffoldDoneMsg:
+		m.creating = false
+		if msg.err != nil {
+			m.createErr = msg.err.Error()
+			return m, nil
+		}
+		return m.finishCreate(msg.name, msg.dir)
+	case launchReadyMsg:
+		m.preparing = ""
+		if msg.err != nil {
+			m.accountErr = msg.err.Error()
+			return m, nil
+		}
+		return m.execLaunch(msg.cmd, msg.accountID, msg.dir)
+	case sessionsMsg:
+		if m.stage == stageResume && msg.dir == m.launchDir {
+			m.sessions = msg.sessions
+			m.sessionsLoaded = true
+			m.sessionErr = ""
+			if msg.err != nil {
+				m.sessionErr = msg.err.Error()
+			}
+		}
+		return m, nil
+	case previewMsg:
+		delete(m.previewPending, msg.dir)
+		m.previews[msg.dir] = msg.preview
+		return m, nil
 	case execDoneMsg:
 		m.err = msg.err
 		return m, tea.Quit
@@ -175,21 +365,38 @@ func (m PickerModel) updateProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.createErr = ""
 			return m, nil
 		}
+		if ref, ok := m.pinnedAtCursor(); ok {
+			m.selected = ref
+			m.launchDir = m.cfg.RefDir(ref)
+			return m.startAccountSelection()
+		}
+		if rp, ok := m.recentAtCursor(); ok {
+			m.selected = m.recentLabel(rp)
+			m.launchDir = rp.Path
+			for i, a := range m.accounts {
+				if a.ID == rp.Account {
+					m.accountIdx = i
+					break
+				}
+			}
+			return m.startAccountSelection()
+		}
 		if m.cursor > 0 && m.cursor <= len(m.filtered) {
 			m.selected = m.filtered[m.cursor-1]
-			m.launchDir = filepath.Join(m.browseDir, m.selected)
+			m.launchDir = m.filteredDirs[m.cursor-1]
 			return m.startAccountSelection()
 		}
 	case tea.KeyRight:
 		if m.cursor > 0 && m.cursor <= len(m.filtered) {
-			selected := m.filtered[m.cursor-1]
 			m.browseStack = append(m.browseStack, browseEntry{
 				dir:        m.browseDir,
+				root:       m.browseRoot,
 				cursor:     m.cursor,
 				viewOffset: m.viewOffset,
 				filter:     m.filter,
 			})
-			m.browseDir = filepath.Join(m.browseDir, selected)
+			m.browseDir = m.filteredDirs[m.cursor-1]
+			m.browseRoot = m.filteredRoots[m.cursor-1]
 			m.filter = ""
 			m.refreshProjects()
 		}
@@ -198,12 +405,16 @@ func (m PickerModel) updateProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			entry := m.browseStack[len(m.browseStack)-1]
 			m.browseStack = m.browseStack[:len(m.browseStack)-1]
 			m.browseDir = entry.dir
+			m.browseRoot = entry.root
 			m.filter = entry.filter
 			m.refreshProjects()
 			m.cursor = entry.cursor
 			if m.cursor > len(m.filtered) {
 				m.cursor = len(m.filtered)
 			}
+			if n := m.shortcutCount(); m.cursor < -n {
+				m.cursor = -n
+			}
 			m.viewOffset = entry.viewOffset
 			maxOff := len(m.filtered) - (m.maxVisible() - 1)
 			if maxOff < 0 {
@@ -213,8 +424,14 @@ func (m PickerModel) updateProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.viewOffset = maxOff
 			}
 		}
+	case tea.KeyCtrlP:
+		m.togglePin()
+	case tea.KeyTab:
+		m.cycleRoot()
+	case tea.KeyCtrlT:
+		m.showPreview = !m.showPreview
 	case tea.KeyUp:
-		if m.cursor > 0 {
+		if m.cursor > -m.shortcutCount() {
 			m.cursor--
 			if m.cursor > 0 {
 				projectIdx := m.cursor - 1
@@ -252,11 +469,22 @@ func (m PickerModel) updateProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	return m, nil
+	return m, tea.Batch(m.loadGitStatus(), m.loadPreview())
 }
 
 func (m PickerModel) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.creating && msg.Type != tea.KeyCtrlC {
+		return m, nil
+	}
 	switch msg.Type {
+	case tea.KeyUp:
+		if m.templateIdx > 0 {
+			m.templateIdx--
+		}
+	case tea.KeyDown:
+		if m.templateIdx < len(m.cfg.Templates) {
+			m.templateIdx++
+		}
 	case tea.KeyEsc:
 		m.stage = stageProject
 		m.createErr = ""
@@ -289,6 +517,9 @@ func (m PickerModel) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m PickerModel) updateAccount(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.preparing != "" && msg.Type != tea.KeyCtrlC {
+		return m, nil
+	}
 	switch msg.Type {
 	case tea.KeyEsc:
 		m.stage = stageProject
@@ -308,19 +539,97 @@ func (m PickerModel) updateAccount(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.accountIdx < len(m.accounts)-1 {
 			m.accountIdx++
 		}
+	case tea.KeyRunes:
+		switch string(msg.Runes) {
+		case "w":
+			if m.canWorktree {
+				m.useWorktree = !m.useWorktree
+				m.accountErr = ""
+			}
+		case "p":
+			if len(m.accounts) > 0 {
+				return m.startPrompt(m.accounts[m.accountIdx])
+			}
+		case "r":
+			if len(m.accounts) > 0 {
+				return m.startResume(m.accounts[m.accountIdx])
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// startPrompt opens the prompt input for account, or explains that the
+// account has no way to take one.
+func (m PickerModel) startPrompt(account config.Account) (tea.Model, tea.Cmd) {
+	if !account.SupportsPrompt() {
+		m.accountErr = fmt.Sprintf("%s doesn't support an initial prompt", account.Label)
+		return m, nil
 	}
+	m.stage = stagePrompt
+	m.promptInput = m.prompt
+	m.accountErr = ""
+	return m, nil
+}
 
+func (m PickerModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.stage = stageAccount
+		return m, nil
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.prompt = strings.TrimSpace(m.promptInput)
+		return m.launchAccount(m.accounts[m.accountIdx])
+	case tea.KeyBackspace:
+		if r := []rune(m.promptInput); len(r) > 0 {
+			m.promptInput = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		m.promptInput = ""
+	default:
+		if msg.Type == tea.KeyRunes {
+			for _, r := range msg.Runes {
+				if r == '\n' || r == '\r' || r == '\t' {
+					r = ' '
+				}
+				if r > 31 && r != 127 {
+					m.promptInput += string(r)
+				}
+			}
+		} else if msg.Type == tea.KeySpace {
+			m.promptInput += " "
+		}
+	}
 	return m, nil
 }
 
 func (m PickerModel) startAccountSelection() (tea.Model, tea.Cmd) {
+	if m.pathOnly {
+		m.pickedDir = m.launchDir
+		m.quitting = true
+		return m, tea.Quit
+	}
 	if len(m.accounts) == 0 {
 		m.stage = stageProject
 		m.statusMsg = "No enabled tools configured. Run qs setup or qs accounts."
 		m.statusErr = true
 		return m, nil
 	}
-	if len(m.accounts) == 1 {
+	m.canWorktree = worktree.IsRepo(m.launchDir)
+	m.useWorktree = false
+	m.accountErr = ""
+	projectEnv, err := config.ProjectEnv(m.cfg, m.launchDir)
+	if err != nil {
+		m.accountErr = err.Error()
+	}
+	m.projectEnv = projectEnv
+	// With one account there is nothing to choose, unless it's whether to
+	// use a worktree
+	if len(m.accounts) == 1 && !m.canWorktree {
 		return m.launchAccount(m.accounts[0])
 	}
 	m.stage = stageAccount
@@ -328,16 +637,52 @@ func (m PickerModel) startAccountSelection() (tea.Model, tea.Cmd) {
 }
 
 func (m PickerModel) launchAccount(account config.Account) (tea.Model, tea.Cmd) {
-	m.cfg.LastAccount = account.ID
-	_ = config.Save(m.cfg, "")
+	args, err := account.PromptedArgs(m.prompt)
+	if err != nil {
+		// Say so rather than launching without the prompt
+		m.stage = stageAccount
+		m.accountErr = err.Error()
+		return m, nil
+	}
+	return m.launch(account, args, m.useWorktree)
+}
 
-	projectDir := m.launchDir
-	c := exec.Command(account.Command, account.ResolvedArgs()...)
-	c.Dir = projectDir
+// launch runs account's command with args in launchDir, or in a fresh
+// worktree of it. Secret references and git worktree add can both take a
+// while, so the launch is prepared in the background.
+func (m PickerModel) launch(account config.Account, args []string, useWorktree bool) (tea.Model, tea.Cmd) {
+	m.stage = stageAccount
+	m.preparing = "resolving keys"
+	if useWorktree {
+		m.preparing = "creating worktree"
+	}
+	m.accountErr = ""
+
+	cfg, keys, root, dir := m.cfg, m.keys, m.cfg.WorktreeRoot(), m.launchDir
+	return m, func() tea.Msg {
+		c := exec.Command(account.Command, args...)
+		// Inject API keys and project env as env vars, resolving references
+		// before anything records the launch or creates a worktree
+		if err := applyLaunchEnv(c, cfg, keys, account, dir); err != nil {
+			return launchReadyMsg{err: err}
+		}
+		if !useWorktree {
+			return launchReadyMsg{cmd: c, accountID: account.ID, dir: dir}
+		}
+		_, wtDir, err := worktree.Create(root, dir)
+		return launchReadyMsg{cmd: c, accountID: account.ID, dir: wtDir, err: err}
+	}
+}
 
-	// Inject API keys as env vars
-	applyAccountEnv(c, m.keys, account.ID)
+// execLaunch records a launch of accountID and hands the terminal to c,
+// running in dir.
+func (m PickerModel) execLaunch(c *exec.Cmd, accountID, dir string) (tea.Model, tea.Cmd) {
+	m.cfg.LastAccount = accountID
+	_ = config.Save(m.cfg, "")
+	// History tracks the project, not the throwaway worktree
+	_ = config.RecordLaunch(m.launchDir, accountID)
 
+	c.Dir = dir
 	return m, tea.ExecProcess(c, func(err error) tea.Msg {
 		return execDoneMsg{err: err}
 	})
@@ -350,7 +695,7 @@ func (m PickerModel) createProjectAndContinue() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	projectPath := filepath.Join(m.browseDir, name)
+	projectPath := filepath.Join(m.createDir(), name)
 	info, statErr := os.Stat(projectPath)
 	if statErr == nil {
 		m.stage = stageProject
@@ -361,7 +706,7 @@ func (m PickerModel) createProjectAndContinue() (tea.Model, tea.Cmd) {
 			m.statusErr = true
 			m.filter = ""
 			m.applyFilter()
-			m.setCursorForProject(name)
+			m.setCursorForProject(projectPath)
 			return m, nil
 		}
 		m.statusMsg = fmt.Sprintf("A file named \"%s\" already exists.", name)
@@ -373,14 +718,29 @@ func (m PickerModel) createProjectAndContinue() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.templateIdx > 0 && m.templateIdx <= len(m.cfg.Templates) {
+		tmpl := m.cfg.Templates[m.templateIdx-1]
+		m.creating = true
+		m.createErr = ""
+		return m, func() tea.Msg {
+			err := scaffold.Create(projectPath, tmpl, scaffold.Vars(projectPath, tmpl))
+			return scaffoldDoneMsg{name: name, dir: projectPath, err: err}
+		}
+	}
+
 	if err := os.Mkdir(projectPath, 0755); err != nil {
 		m.createErr = fmt.Sprintf("Failed to create folder: %v", err)
 		return m, nil
 	}
+	return m.finishCreate(name, projectPath)
+}
 
+// finishCreate selects a newly created project folder and moves on to the
+// account stage.
+func (m PickerModel) finishCreate(name, projectPath string) (tea.Model, tea.Cmd) {
 	m.refreshProjects()
 	m.selected = name
-	m.launchDir = filepath.Join(m.browseDir, name)
+	m.launchDir = projectPath
 	m.stage = stageProject
 	m.createErr = ""
 	m.statusMsg = ""
@@ -389,20 +749,39 @@ func (m PickerModel) createProjectAndContinue() (tea.Model, tea.Cmd) {
 }
 
 func (m *PickerModel) applyFilter() {
-	if m.filter == "" {
-		m.filtered = m.projects
-	} else {
-		q := strings.ToLower(m.filter)
-		var result []string
-		for _, p := range m.projects {
-			if strings.Contains(strings.ToLower(p), q) {
-				result = append(result, p)
-			}
+	query, status := parseStatusFilter(m.filter)
+	m.pinned = nil
+	for _, p := range fuzzyFilter(query, m.existingFavorites()) {
+		if m.statusMatches(status, m.cfg.RefDir(p.str)) {
+			m.pinned = append(m.pinned, p)
 		}
-		m.filtered = result
 	}
 
-	if len(m.filtered) > 0 {
+	// Pinned projects are listed in their own section, not again below
+	results := fuzzyFilter(query, m.projects)
+	m.filtered = make([]string, 0, len(results))
+	m.filteredDirs = make([]string, 0, len(results))
+	m.filteredRoots = make([]string, 0, len(results))
+	m.highlights = make([][]int, 0, len(results))
+	for _, r := range results {
+		if m.cfg.IsFavorite(m.cfg.ProjectRef(m.projectDirs[r.index])) {
+			continue
+		}
+		if !m.statusMatches(status, m.projectDirs[r.index]) {
+			continue
+		}
+		m.filtered = append(m.filtered, r.str)
+		m.filteredDirs = append(m.filteredDirs, m.projectDirs[r.index])
+		m.filteredRoots = append(m.filteredRoots, m.projectRoots[r.index])
+		m.highlights = append(m.highlights, r.positions)
+	}
+
+	// Start on the top shortcut, except when browsing a subdirectory
+	// unfiltered: then its contents are what the user came for
+	browsing := m.filter == "" && !m.atTop()
+	if n := m.shortcutCount(); n > 0 && !(browsing && len(m.filtered) > 0) {
+		m.cursor = -n
+	} else if len(m.filtered) > 0 {
 		m.cursor = 1
 	} else {
 		m.cursor = 0
@@ -410,14 +789,196 @@ func (m *PickerModel) applyFilter() {
 	m.viewOffset = 0
 }
 
+// existingFavorites returns the pinned projects that still exist.
+func (m PickerModel) existingFavorites() []string {
+	var favorites []string
+	for _, f := range m.cfg.Favorites {
+		ref := config.FavoriteKey(f)
+		if info, err := os.Stat(m.cfg.RefDir(ref)); err == nil && info.IsDir() {
+			favorites = append(favorites, ref)
+		}
+	}
+	return favorites
+}
+
+// togglePin pins or unpins the project under the cursor and saves the config.
+func (m *PickerModel) togglePin() {
+	var rel string
+	if pin, ok := m.pinnedAtCursor(); ok {
+		rel = pin
+	} else if rp, ok := m.recentAtCursor(); ok {
+		rel = m.recentLabel(rp)
+	} else if m.cursor > 0 && m.cursor <= len(m.filtered) {
+		rel = m.cfg.ProjectRef(m.filteredDirs[m.cursor-1])
+	} else {
+		return
+	}
+
+	pinned := m.cfg.ToggleFavorite(rel)
+	m.statusErr = false
+	if err := config.Save(m.cfg, ""); err != nil {
+		m.statusMsg = fmt.Sprintf("Failed to save favorites: %v", err)
+		m.statusErr = true
+	} else if pinned {
+		m.statusMsg = fmt.Sprintf("Pinned %s", rel)
+	} else {
+		m.statusMsg = fmt.Sprintf("Unpinned %s", rel)
+	}
+
+	cursor := m.cursor
+	m.applyFilter()
+	if pinned {
+		m.setCursorForPin(rel)
+	} else if cursor >= 0 {
+		// Keep the cursor in place; the unpinned row moves down into the list
+		m.cursor = cursor
+		if m.cursor > len(m.filtered) {
+			m.cursor = len(m.filtered)
+		}
+	}
+}
+
+// setCursorForPin moves the cursor to the pinned row for rel.
+func (m *PickerModel) setCursorForPin(rel string) {
+	for i, p := range m.pinned {
+		if p.str == rel {
+			m.cursor = i - m.shortcutCount()
+			return
+		}
+	}
+}
+
+// visibleRecent returns the Recent section's entries, which are only shown
+// at the top level with no filter typed. Pinned projects are left out.
+func (m PickerModel) visibleRecent() []config.RecentProject {
+	if m.filter != "" || m.rootFilter != "" || !m.atTop() {
+		return nil
+	}
+	var recent []config.RecentProject
+	for _, rp := range m.recent {
+		if !m.cfg.IsFavorite(m.recentLabel(rp)) {
+			recent = append(recent, rp)
+		}
+	}
+	return recent
+}
+
+// shortcutCount is the number of pinned and recent rows above
+// create-new-folder.
+func (m PickerModel) shortcutCount() int {
+	return len(m.pinned) + len(m.visibleRecent())
+}
+
+// pinnedAtCursor returns the pinned project under the cursor, if any.
+func (m PickerModel) pinnedAtCursor() (string, bool) {
+	idx := m.shortcutCount() + m.cursor
+	if m.cursor >= 0 || idx < 0 || idx >= len(m.pinned) {
+		return "", false
+	}
+	return m.pinned[idx].str, true
+}
+
+// recentAtCursor returns the Recent entry under the cursor, if any.
+func (m PickerModel) recentAtCursor() (config.RecentProject, bool) {
+	recent := m.visibleRecent()
+	idx := len(recent) + m.cursor
+	if m.cursor >= 0 || idx < 0 {
+		return config.RecentProject{}, false
+	}
+	return recent[idx], true
+}
+
+// recentLabel is a Recent entry's project reference, e.g. "api" or "src:api".
+func (m PickerModel) recentLabel(rp config.RecentProject) string {
+	return m.cfg.ProjectRef(rp.Path)
+}
+
+// refreshProjects rescans the current level: the merged roots at the top,
+// the browse directory's subfolders below it.
 func (m *PickerModel) refreshProjects() {
-	m.projects = scanProjects(m.browseDir)
+	m.projects, m.projectDirs, m.projectRoots = nil, nil, nil
+	if !m.atTop() {
+		for _, name := range scanProjects(m.browseDir) {
+			m.projects = append(m.projects, name)
+			m.projectDirs = append(m.projectDirs, filepath.Join(m.browseDir, name))
+			m.projectRoots = append(m.projectRoots, m.browseRoot)
+		}
+		m.applyFilter()
+		return
+	}
+
+	for _, p := range mergeProjects(m.cfg, m.discovered, m.rootFilter) {
+		m.projects = append(m.projects, p.Name)
+		m.projectDirs = append(m.projectDirs, p.Dir)
+		m.projectRoots = append(m.projectRoots, p.Root)
+	}
 	m.applyFilter()
 }
 
-func (m *PickerModel) setCursorForProject(name string) {
-	for i, project := range m.filtered {
-		if project == name {
+// atTop reports whether the picker shows the merged roots rather than a
+// browsed subdirectory.
+func (m PickerModel) atTop() bool {
+	return len(m.browseStack) == 0
+}
+
+// multiRoot reports whether more than one project root is configured.
+func (m PickerModel) multiRoot() bool {
+	return len(m.cfg.ProjectRoots()) > 1
+}
+
+// cycleRoot narrows the top level to the next root, then back to all roots.
+func (m *PickerModel) cycleRoot() {
+	if !m.atTop() || !m.multiRoot() {
+		return
+	}
+	roots := m.cfg.ProjectRoots()
+	next := roots[0].Name
+	for i, r := range roots {
+		if r.Name == m.rootFilter {
+			next = ""
+			if i+1 < len(roots) {
+				next = roots[i+1].Name
+			}
+			break
+		}
+	}
+	m.rootFilter = next
+	m.refreshProjects()
+}
+
+// createDir is where create-new-folder makes the folder: the browse
+// directory, or at the top the root being filtered to (the primary root
+// when all roots are shown).
+func (m PickerModel) createDir() string {
+	if m.atTop() && m.rootFilter != "" {
+		for _, r := range m.cfg.ProjectRoots() {
+			if r.Name == m.rootFilter {
+				return r.Path
+			}
+		}
+	}
+	return m.browseDir
+}
+
+// keepCursor runs update, which rebuilds the list, and puts the cursor back
+// where it was: on the same project, or on the same shortcut row.
+func (m *PickerModel) keepCursor(update func()) {
+	cursor, viewOffset, dir := m.cursor, m.viewOffset, ""
+	if cursor > 0 && cursor <= len(m.filteredDirs) {
+		dir = m.filteredDirs[cursor-1]
+	}
+	update()
+	if dir != "" {
+		m.viewOffset = viewOffset
+		m.setCursorForProject(dir)
+	} else if cursor <= 0 && cursor >= -m.shortcutCount() {
+		m.cursor = cursor
+	}
+}
+
+func (m *PickerModel) setCursorForProject(dir string) {
+	for i, projectDir := range m.filteredDirs {
+		if projectDir == dir {
 			m.cursor = i + 1
 			projectRows := m.maxVisible() - 1
 			if projectRows < 1 {
@@ -436,9 +997,15 @@ func (m *PickerModel) setCursorForProject(name string) {
 func (m PickerModel) maxVisible() int {
 	if m.height > 0 {
 		max := m.height - 8 // header + filter + separator + footer
-		if m.browseDir != m.cfg.ProjectsRoot {
+		if !m.atTop() {
 			max-- // breadcrumb line
 		}
+		if len(m.pinned) > 0 {
+			max -= len(m.pinned) + 2 // heading + entries + blank line
+		}
+		if recent := m.visibleRecent(); len(recent) > 0 {
+			max -= len(recent) + 2
+		}
 		if max < 1 {
 			return 1
 		}
@@ -457,6 +1024,10 @@ func (m PickerModel) View() string {
 		return m.viewProject()
 	case stageCreate:
 		return m.viewCreate()
+	case stagePrompt:
+		return m.viewPrompt()
+	case stageResume:
+		return m.viewResume()
 	default:
 		return m.viewAccount()
 	}
@@ -475,10 +1046,17 @@ func (m PickerModel) viewProject() string {
 	sel := lipgloss.NewStyle().Foreground(ColorBrCyan).Bold(true)
 
 	s.WriteString("\n")
-	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render("- select project")))
-	if m.browseDir != m.cfg.ProjectsRoot {
-		rel, _ := filepath.Rel(m.cfg.ProjectsRoot, m.browseDir)
-		segments := strings.Split(filepath.ToSlash(rel), "/")
+	header := "- select project"
+	if m.pathOnly {
+		header = "- go to project"
+	}
+	if m.rootFilter != "" {
+		header += " in " + m.rootFilter
+	}
+	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render(header)))
+	if !m.atTop() {
+		ref := m.cfg.ProjectRef(m.browseDir)
+		segments := strings.Split(ref, "/")
 		breadcrumb := strings.Join(segments, " > ")
 		s.WriteString(fmt.Sprintf("  %s\n", dim.Render(breadcrumb)))
 	}
@@ -492,14 +1070,57 @@ func (m PickerModel) viewProject() string {
 
 	s.WriteString(fmt.Sprintf("  %s\n", dim.Render("---------------------------------")))
 
+	var list strings.Builder
+	if len(m.pinned) > 0 {
+		list.WriteString(fmt.Sprintf("  %s\n", title.Render("pinned")))
+		for i, p := range m.pinned {
+			if m.cursor == i-m.shortcutCount() {
+				list.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), highlightMatches(p.str, p.positions, white, sel)))
+			} else {
+				list.WriteString(fmt.Sprintf("    %s\n", highlightMatches(p.str, p.positions, dim, title)))
+			}
+		}
+		list.WriteString("\n")
+	}
+
+	if recent := m.visibleRecent(); len(recent) > 0 {
+		list.WriteString(fmt.Sprintf("  %s\n", title.Render("recent")))
+		labels := make([]string, len(recent))
+		width := 0
+		for i, rp := range recent {
+			labels[i] = m.recentLabel(rp)
+			if w := lipgloss.Width(labels[i]); w > width {
+				width = w
+			}
+		}
+		now := time.Now()
+		for i, rp := range recent {
+			label := labels[i] + strings.Repeat(" ", width-lipgloss.Width(labels[i]))
+			meta := dim.Render(fmt.Sprintf("%s  %s", rp.Account, FormatAge(now.Sub(rp.LastUsed))))
+			if m.cursor == i-len(recent) {
+				list.WriteString(fmt.Sprintf("  %s %s  %s\n", sel.Render(">"), white.Render(label), meta))
+			} else {
+				list.WriteString(fmt.Sprintf("    %s  %s\n", dim.Render(label), meta))
+			}
+		}
+		list.WriteString("\n")
+	}
+
 	if m.cursor == 0 {
-		s.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), white.Render("+ create new folder")))
+		list.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), white.Render("+ create new folder")))
 	} else {
-		s.WriteString(fmt.Sprintf("    %s\n", dim.Render("+ create new folder")))
+		list.WriteString(fmt.Sprintf("    %s\n", dim.Render("+ create new folder")))
 	}
 
 	if len(m.filtered) == 0 {
-		s.WriteString(fmt.Sprintf("\n  %s\n", dim.Render("no matches")))
+		_, status := parseStatusFilter(m.filter)
+		if m.indexing && m.atTop() {
+			list.WriteString(fmt.Sprintf("\n  %s\n", dim.Render("indexing projects...")))
+		} else if status.active() && len(m.gitPending) > 0 {
+			list.WriteString(fmt.Sprintf("\n  %s\n", dim.Render("reading git status...")))
+		} else if len(m.pinned) == 0 {
+			list.WriteString(fmt.Sprintf("\n  %s\n", dim.Render("no matches")))
+		}
 	} else {
 		maxOff := len(m.filtered) - maxShow
 		if maxOff < 0 {
@@ -510,17 +1131,68 @@ func (m PickerModel) viewProject() string {
 			viewOffset = maxOff
 		}
 
+		// Merged roots get a root label column, repositories a git status
+		// column once their status has loaded
+		showRoots := m.atTop() && m.multiRoot()
+		showGit := false
+		width, rootWidth, branchWidth := 0, 0, 0
+		for i := 0; i < maxShow && viewOffset+i < len(m.filtered); i++ {
+			idx := viewOffset + i
+			if w := lipgloss.Width(m.filtered[idx]); w > width {
+				width = w
+			}
+			if w := lipgloss.Width(m.filteredRoots[idx]); w > rootWidth {
+				rootWidth = w
+			}
+			if e := m.gitStatus[m.filteredDirs[idx]]; e.ok {
+				showGit = true
+				if w := lipgloss.Width(truncateBranch(e.status.Branch)); w > branchWidth {
+					branchWidth = w
+				}
+			}
+		}
+
 		for i := 0; i < maxShow && viewOffset+i < len(m.filtered); i++ {
 			idx := viewOffset + i
 			name := m.filtered[idx]
+			var positions []int
+			if idx < len(m.highlights) {
+				positions = m.highlights[idx]
+			}
+			cols := ""
+			if showRoots || showGit {
+				cols = strings.Repeat(" ", width-lipgloss.Width(name))
+			}
+			if showRoots {
+				root := m.filteredRoots[idx]
+				cols += "  " + dim.Render(root+strings.Repeat(" ", rootWidth-lipgloss.Width(root)))
+			}
+			if e := m.gitStatus[m.filteredDirs[idx]]; e.ok {
+				cols += "  " + renderGitStatus(e.status, branchWidth, dim)
+			}
 			if m.cursor > 0 && idx == m.cursor-1 {
-				s.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), white.Render(name)))
+				list.WriteString(fmt.Sprintf("  %s %s%s\n", sel.Render(">"), highlightMatches(name, positions, white, sel), cols))
 			} else {
-				s.WriteString(fmt.Sprintf("    %s\n", dim.Render(name)))
+				list.WriteString(fmt.Sprintf("    %s%s\n", highlightMatches(name, positions, dim, title), cols))
 			}
 		}
 	}
 
+	if m.previewVisible() {
+		listWidth := m.width * 55 / 100
+		height := lipgloss.Height(list.String())
+		if height < 12 {
+			height = 12
+		}
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().MaxWidth(listWidth).Render(strings.TrimRight(list.String(), "\n")),
+			"  ",
+			m.viewPreview(m.width-listWidth-3, height)))
+		s.WriteString("\n")
+	} else {
+		s.WriteString(list.String())
+	}
+
 	s.WriteString("\n")
 	if m.statusMsg != "" {
 		statusStyle := dim
@@ -531,17 +1203,28 @@ func (m PickerModel) viewProject() string {
 		s.WriteString("\n")
 	}
 
-	if len(m.filtered) > maxShow {
-		s.WriteString(fmt.Sprintf("  %s navigate  %s browse  %s  %s quit\n",
+	rootHint := ""
+	if m.atTop() && m.multiRoot() {
+		rootHint = fmt.Sprintf("  %s root", dim.Render("tab"))
+	}
+	if m.width >= minPreviewWidth {
+		rootHint += fmt.Sprintf("  %s preview", dim.Render("ctrl+t"))
+	}
+	if len(m.filtered) > maxShow && m.cursor >= 0 {
+		s.WriteString(fmt.Sprintf("  %s navigate  %s browse  %s pin%s  %s  %s quit\n",
 			dim.Render("up/down"),
 			dim.Render("left/right"),
+			dim.Render("ctrl+p"),
+			rootHint,
 			dim.Render(fmt.Sprintf("(%d/%d)", m.cursor+1, len(m.filtered)+1)),
 			dim.Render("esc")))
 	} else {
-		s.WriteString(fmt.Sprintf("  %s navigate  %s browse  %s select  %s quit\n",
+		s.WriteString(fmt.Sprintf("  %s navigate  %s browse  %s select  %s pin%s  %s quit\n",
 			dim.Render("up/down"),
 			dim.Render("left/right"),
 			dim.Render("enter"),
+			dim.Render("ctrl+p"),
+			rootHint,
 			dim.Render("esc")))
 	}
 
@@ -560,6 +1243,9 @@ func (m PickerModel) viewCreate() string {
 	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render("- create folder")))
 	s.WriteString("\n")
 	s.WriteString(fmt.Sprintf("  %s\n", dim.Render("single folder name only (spaces allowed)")))
+	if m.multiRoot() {
+		s.WriteString(fmt.Sprintf("  %s\n", dim.Render("in "+m.createDir())))
+	}
 	s.WriteString("\n")
 
 	if m.createInput == "" {
@@ -568,6 +1254,31 @@ func (m PickerModel) viewCreate() string {
 		s.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), white.Render(m.createInput)))
 	}
 
+	if len(m.cfg.Templates) > 0 {
+		s.WriteString("\n")
+		s.WriteString(fmt.Sprintf("  %s\n", dim.Render("template")))
+		rows := []string{"empty folder"}
+		for _, t := range m.cfg.Templates {
+			rows = append(rows, t.Name)
+		}
+		for i, row := range rows {
+			source := ""
+			if i > 0 {
+				source = templateSource(m.cfg.Templates[i-1])
+			}
+			if i == m.templateIdx {
+				s.WriteString(fmt.Sprintf("  %s %s  %s\n", sel.Render(">"), white.Render(row), dim.Render(truncate(source, 50))))
+			} else {
+				s.WriteString(fmt.Sprintf("    %s  %s\n", dim.Render(row), dim.Render(truncate(source, 50))))
+			}
+		}
+	}
+
+	if m.creating {
+		s.WriteString("\n")
+		s.WriteString(fmt.Sprintf("  %s\n", dim.Render("creating from "+m.cfg.Templates[m.templateIdx-1].Name+"...")))
+	}
+
 	if m.createErr != "" {
 		errStyle := lipgloss.NewStyle().Foreground(ColorRed)
 		s.WriteString("\n")
@@ -575,10 +1286,27 @@ func (m PickerModel) viewCreate() string {
 	}
 
 	s.WriteString("\n")
-	s.WriteString(fmt.Sprintf("  %s save  %s back\n", dim.Render("enter"), dim.Render("esc")))
+	if len(m.cfg.Templates) > 0 {
+		s.WriteString(fmt.Sprintf("  %s template  %s save  %s back\n", dim.Render("up/down"), dim.Render("enter"), dim.Render("esc")))
+	} else {
+		s.WriteString(fmt.Sprintf("  %s save  %s back\n", dim.Render("enter"), dim.Render("esc")))
+	}
 	return s.String()
 }
 
+// templateSource describes where a template's files come from.
+func templateSource(t config.Template) string {
+	switch {
+	case t.Path != "":
+		return t.Path
+	case t.Git != "":
+		return "clone " + t.Git
+	case len(t.Commands) > 0:
+		return strings.Join(t.Commands, " && ")
+	}
+	return ""
+}
+
 func (m PickerModel) viewAccount() string {
 	var s strings.Builder
 
@@ -589,9 +1317,8 @@ func (m PickerModel) viewAccount() string {
 
 	s.WriteString("\n")
 	accountTitle := m.selected
-	if m.browseDir != m.cfg.ProjectsRoot {
-		rel, _ := filepath.Rel(m.cfg.ProjectsRoot, m.browseDir)
-		accountTitle = filepath.ToSlash(rel) + "/" + m.selected
+	if ref := m.cfg.ProjectRef(m.launchDir); ref != m.launchDir {
+		accountTitle = ref
 	}
 	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render("- "+accountTitle)))
 	s.WriteString("\n")
@@ -625,12 +1352,80 @@ func (m PickerModel) viewAccount() string {
 		}
 	}
 
+	if m.canWorktree {
+		mark := "[ ]"
+		if m.useWorktree {
+			mark = green.Render("[x]")
+		}
+		s.WriteString(fmt.Sprintf("\n    %s %s  %s\n",
+			mark,
+			white.Render("new worktree"),
+			dim.Render("fresh branch under "+m.cfg.WorktreeRoot())))
+	}
+
+	if m.prompt != "" {
+		s.WriteString(fmt.Sprintf("\n    %s  %s\n", dim.Render("prompt"), white.Render(truncate(m.prompt, 60))))
+	}
+
+	if m.accountIdx < len(m.accounts) {
+		env := config.MergeEnv(config.AccountEnv(m.keys, m.accounts[m.accountIdx].ID), m.projectEnv)
+		if len(env) > 0 {
+			s.WriteString(fmt.Sprintf("\n    %s  %s\n", dim.Render("env"), dim.Render(truncate(config.EnvNames(env), 100))))
+		}
+	}
+
+	if m.preparing != "" {
+		s.WriteString(fmt.Sprintf("\n  %s\n", dim.Render(m.preparing+"...")))
+	}
+	if m.accountErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(ColorRed)
+		s.WriteString(fmt.Sprintf("\n  %s\n", errStyle.Render(m.accountErr)))
+	}
+
+	s.WriteString("\n")
+	if m.canWorktree {
+		s.WriteString(fmt.Sprintf("  %s navigate  %s select  %s prompt  %s resume  %s worktree  %s back\n",
+			dim.Render("up/down"),
+			dim.Render("enter"),
+			dim.Render("p"),
+			dim.Render("r"),
+			dim.Render("w"),
+			dim.Render("esc")))
+	} else {
+		s.WriteString(fmt.Sprintf("  %s navigate  %s select  %s prompt  %s resume  %s back\n",
+			dim.Render("up/down"),
+			dim.Render("enter"),
+			dim.Render("p"),
+			dim.Render("r"),
+			dim.Render("esc")))
+	}
+
+	return s.String()
+}
+
+func (m PickerModel) viewPrompt() string {
+	var s strings.Builder
+
+	title := lipgloss.NewStyle().Foreground(ColorBrCyan)
+	dim := lipgloss.NewStyle().Foreground(ColorDimGray)
+	white := lipgloss.NewStyle().Foreground(ColorWhite)
+	sel := lipgloss.NewStyle().Foreground(ColorBrCyan).Bold(true)
+
+	account := m.accounts[m.accountIdx]
 	s.WriteString("\n")
-	s.WriteString(fmt.Sprintf("  %s navigate  %s select  %s back\n",
-		dim.Render("up/down"),
-		dim.Render("enter"),
-		dim.Render("esc")))
+	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render("- "+m.cfg.ProjectRef(m.launchDir)+" - "+account.Label)))
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("  %s\n", dim.Render("initial prompt, sent to the tool when it starts (empty for none)")))
+	s.WriteString("\n")
+
+	if m.promptInput == "" {
+		s.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), dim.Render("what should it work on?")))
+	} else {
+		s.WriteString(fmt.Sprintf("  %s %s\n", sel.Render(">"), white.Render(m.promptInput)))
+	}
 
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("  %s launch  %s clear  %s back\n", dim.Render("enter"), dim.Render("ctrl+u"), dim.Render("esc")))
 	return s.String()
 }
 
@@ -644,6 +1439,64 @@ type execDoneMsg struct {
 	err error
 }
 
+// loadRecent returns the most frecent launch directories inside the
+// project roots that still exist.
+func loadRecent(cfg *config.Config) ([]config.RecentProject, error) {
+	h, err := config.LoadHistory("")
+	if err != nil {
+		return nil, err
+	}
+
+	var recent []config.RecentProject
+	for _, rp := range h.Recent(time.Now()) {
+		if cfg.ProjectRef(rp.Path) == rp.Path {
+			continue
+		}
+		if info, err := os.Stat(rp.Path); err != nil || !info.IsDir() {
+			continue
+		}
+		recent = append(recent, rp)
+		if len(recent) == maxRecentProjects {
+			break
+		}
+	}
+	return recent, nil
+}
+
+// discoverRoots converts the configured roots for the discover package.
+func discoverRoots(cfg *config.Config) []discover.Root {
+	var roots []discover.Root
+	for _, r := range cfg.ProjectRoots() {
+		roots = append(roots, discover.Root{Name: r.Name, Path: r.Path})
+	}
+	return roots
+}
+
+// refreshIndex rescans the roots incrementally in the background and saves
+// the index for the next picker to start from.
+func refreshIndex(idx *discover.Index, cfg *config.Config) tea.Cmd {
+	roots := discoverRoots(cfg)
+	opts := discover.Options{Depth: cfg.Discovery.Depth, Markers: cfg.Discovery.Markers}
+	return func() tea.Msg {
+		projects := idx.Refresh(roots, opts)
+		return indexRefreshedMsg{projects: projects, err: discover.SaveIndex(idx, "")}
+	}
+}
+
+// FormatAge renders how long ago something happened, e.g. "5m ago".
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
 // scanProjects reads subdirectories from the projects root.
 func scanProjects(root string) []string {
 	entries, err := os.ReadDir(root)
@@ -675,6 +1528,13 @@ func sanitizeProjectName(input string) (string, error) {
 	if strings.ContainsAny(name, "<>:\"|?*") {
 		return "", fmt.Errorf("folder name contains invalid characters")
 	}
+	// The name ends up in template commands run by the shell
+	if strings.ContainsAny(name, "`$;&'!(){}[]%^~#") {
+		return "", fmt.Errorf("folder name contains shell characters")
+	}
+	if strings.HasPrefix(name, "-") {
+		return "", fmt.Errorf("folder name cannot start with a dash")
+	}
 	if strings.HasSuffix(name, ".") {
 		return "", fmt.Errorf("folder name cannot end with a dot")
 	}
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bcmister/qs/internal/config"
 	tea "github.com/charmbracelet/bubbletea"
@@ -28,6 +30,11 @@ func TestSanitizeProjectName(t *testing.T) {
 		{name: "reserved", input: "CON", wantErr: true},
 		{name: "invalid chars", input: "a:b", wantErr: true},
 		{name: "trailing dot", input: "abc.", wantErr: true},
+		{name: "shell characters", input: "x;rm -rf ~", wantErr: true},
+		{name: "substitution", input: "$(id)", wantErr: true},
+		{name: "quote", input: "it's", wantErr: true},
+		{name: "leading dash", input: "-rf", wantErr: true},
+		{name: "inner dash and dots", input: "api-v2.1", want: "api-v2.1"},
 	}
 
 	for _, tt := range tests {
@@ -76,7 +83,7 @@ func sendKey(m tea.Model, keyType tea.KeyType) tea.Model {
 
 func TestBrowseRightNavigatesIntoSubdir(t *testing.T) {
 	root, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	// cursor starts at 1 (first project = "alpha")
 	if m.cursor != 1 {
@@ -104,7 +111,7 @@ func TestBrowseRightNavigatesIntoSubdir(t *testing.T) {
 
 func TestBrowseLeftRestoresState(t *testing.T) {
 	_, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	// Navigate into alpha
 	result := sendKey(m, tea.KeyRight)
@@ -125,7 +132,7 @@ func TestBrowseLeftRestoresState(t *testing.T) {
 
 func TestBrowseLeftAtRootIsNoop(t *testing.T) {
 	_, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	result := sendKey(m, tea.KeyLeft)
 	pm := result.(PickerModel)
@@ -140,7 +147,7 @@ func TestBrowseLeftAtRootIsNoop(t *testing.T) {
 
 func TestBrowseRightOnCreateRowIsNoop(t *testing.T) {
 	_, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 	// Move cursor to 0 (create new folder)
 	m.cursor = 0
 
@@ -154,7 +161,7 @@ func TestBrowseRightOnCreateRowIsNoop(t *testing.T) {
 
 func TestBrowseIntoEmptyDir(t *testing.T) {
 	root, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	// Move to beta (index 1 in filtered = "beta" is second, cursor=2)
 	m.cursor = 2
@@ -180,7 +187,7 @@ func TestBrowseIntoEmptyDir(t *testing.T) {
 
 func TestBreadcrumbShownWhenBrowsing(t *testing.T) {
 	_, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	// Navigate into alpha
 	result := sendKey(m, tea.KeyRight)
@@ -194,7 +201,7 @@ func TestBreadcrumbShownWhenBrowsing(t *testing.T) {
 
 func TestLaunchDirSetOnEnter(t *testing.T) {
 	root, cfg := setupTestDirs(t)
-	m := NewPicker(cfg)
+	m := NewPicker(cfg, nil)
 
 	// Navigate into alpha
 	result := sendKey(m, tea.KeyRight)
@@ -250,3 +257,545 @@ func TestScanProjects(t *testing.T) {
 	}
 }
 
+
+func TestWorktreeOptionOnlyForGitRepos(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root, cfg := setupTestDirs(t)
+	if out, err := exec.Command("git", "init", "-q", filepath.Join(root, "alpha")).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	wKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")}
+
+	// alpha is a repo: w toggles the worktree option
+	pm := sendKey(NewPicker(cfg, nil), tea.KeyEnter).(PickerModel)
+	if pm.stage != stageAccount || !pm.canWorktree {
+		t.Fatalf("expected account stage with worktree option, got stage=%d canWorktree=%v", pm.stage, pm.canWorktree)
+	}
+	updated, _ := pm.Update(wKey)
+	pm = updated.(PickerModel)
+	if !pm.useWorktree {
+		t.Error("expected w to enable the worktree option")
+	}
+	if !strings.Contains(pm.View(), "new worktree") {
+		t.Error("expected worktree option in account view")
+	}
+
+	// Going back and choosing beta (not a repo) resets and hides it
+	pm = sendKey(pm, tea.KeyEsc).(PickerModel)
+	pm = sendKey(pm, tea.KeyDown).(PickerModel)
+	pm = sendKey(pm, tea.KeyEnter).(PickerModel)
+	if pm.canWorktree || pm.useWorktree {
+		t.Errorf("worktree option should be unavailable outside a repo (can=%v use=%v)", pm.canWorktree, pm.useWorktree)
+	}
+	updated, _ = pm.Update(wKey)
+	if updated.(PickerModel).useWorktree {
+		t.Error("w should be a no-op outside a repo")
+	}
+}
+
+func TestWorktreeCreatedInBackground(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	root, cfg := setupTestDirs(t)
+	cfg.Accounts = cfg.Accounts[:1]
+	cfg.WorktreeDir = filepath.Join(home, "worktrees")
+	alpha := filepath.Join(root, "alpha")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=qs", "-c", "user.email=qs@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", alpha}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// A single account still stops at the account stage to offer w
+	pm := sendKey(NewPicker(cfg, nil), tea.KeyEnter).(PickerModel)
+	if pm.stage != stageAccount || !pm.canWorktree {
+		t.Fatalf("expected account stage with worktree option, got stage=%d canWorktree=%v", pm.stage, pm.canWorktree)
+	}
+	updated, _ := pm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	pm = updated.(PickerModel)
+	if pm.preparing == "" || cmd == nil {
+		t.Fatalf("expected the worktree to be created by a command, preparing=%q", pm.preparing)
+	}
+	if !strings.Contains(pm.View(), "creating worktree") {
+		t.Error("expected a creating worktree note in the account view")
+	}
+	if _, err := os.Stat(cfg.WorktreeRoot()); !os.IsNotExist(err) {
+		t.Error("worktree created before the command ran")
+	}
+
+	msg, ok := cmd().(launchReadyMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("expected launchReadyMsg, got %+v", msg)
+	}
+	if !strings.HasPrefix(msg.dir, cfg.WorktreeRoot()) {
+		t.Errorf("worktree dir %s not under %s", msg.dir, cfg.WorktreeRoot())
+	}
+	updated, cmd = pm.Update(msg)
+	if pm = updated.(PickerModel); pm.preparing != "" || cmd == nil || msg.cmd.Dir != msg.dir {
+		t.Errorf("expected launch in the worktree, preparing=%q dir=%s", pm.preparing, msg.cmd.Dir)
+	}
+}
+
+func TestLaunchResolvesKeysInBackground(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	_, cfg := setupTestDirs(t)
+	cfg.Accounts = cfg.Accounts[:1]
+	keys := config.AccountKeys{}
+	config.SetAccountKey(keys, cfg.Accounts[0].ID, "API_KEY", "cmd:exit 3")
+
+	// One account and no git repository: Enter launches straight away
+	updated, cmd := NewPicker(cfg, keys).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	pm := updated.(PickerModel)
+	if pm.preparing == "" || cmd == nil || pm.accountErr != "" {
+		t.Fatalf("expected the keys to be resolved by a command, preparing=%q err=%q", pm.preparing, pm.accountErr)
+	}
+
+	msg, ok := cmd().(launchReadyMsg)
+	if !ok || msg.err == nil {
+		t.Fatalf("expected a failed launchReadyMsg, got %+v", msg)
+	}
+	updated, cmd = pm.Update(msg)
+	pm = updated.(PickerModel)
+	if pm.preparing != "" || cmd != nil || !strings.Contains(pm.accountErr, "cmd:exit 3") {
+		t.Errorf("expected the error in the account stage, preparing=%q err=%q", pm.preparing, pm.accountErr)
+	}
+	if cfg.LastAccount != "" {
+		t.Errorf("launch recorded despite the failed key, LastAccount=%q", cfg.LastAccount)
+	}
+}
+
+func TestFilterFuzzySortsByScore(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"requests", "quick-server", "qs-rev", "unrelated"} {
+		os.MkdirAll(filepath.Join(root, name), 0755)
+	}
+	m := NewPicker(&config.Config{ProjectsRoot: root}, nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("qsrv")})
+	pm := updated.(PickerModel)
+
+	if len(pm.filtered) != 2 {
+		t.Fatalf("expected 2 matches, got %v", pm.filtered)
+	}
+	if pm.cursor != 1 {
+		t.Errorf("expected cursor on best match, got %d", pm.cursor)
+	}
+	if len(pm.highlights) != len(pm.filtered) || len(pm.highlights[0]) != 4 {
+		t.Errorf("expected 4 highlighted runes for the best match, got %v", pm.highlights)
+	}
+
+	// Clearing the filter restores the alphabetical list without highlights
+	for range "qsrv" {
+		pm = sendKey(pm, tea.KeyBackspace).(PickerModel)
+	}
+	if len(pm.filtered) != 4 || pm.filtered[0] != "qs-rev" || pm.highlights[0] != nil {
+		t.Errorf("expected unfiltered alphabetical list, got %v", pm.filtered)
+	}
+}
+
+func TestRecentSectionFromHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	root, cfg := setupTestDirs(t)
+	now := time.Now()
+	h := &config.History{}
+	h.Add(filepath.Join(root, "beta"), "test", now.Add(-48*time.Hour))
+	h.Add(filepath.Join(root, "alpha", "sub2"), "test2", now.Add(-time.Hour))
+	h.Add(filepath.Join(root, "gone"), "test", now) // vanished
+	h.Add(t.TempDir(), "test", now)                 // outside the root
+	if err := config.SaveHistory(h, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewPicker(cfg, nil)
+	if len(m.recent) != 2 {
+		t.Fatalf("expected 2 recent projects, got %+v", m.recent)
+	}
+	if m.cursor != -2 {
+		t.Fatalf("expected cursor on the top recent entry, got %d", m.cursor)
+	}
+	view := m.View()
+	if !strings.Contains(view, "recent") || !strings.Contains(view, "alpha/sub2") {
+		t.Errorf("expected Recent section with alpha/sub2, got:\n%s", view)
+	}
+
+	// Enter launches the most frecent entry with its last account selected
+	pm := sendKey(m, tea.KeyEnter).(PickerModel)
+	if pm.stage != stageAccount {
+		t.Fatalf("expected account stage, got %d", pm.stage)
+	}
+	if pm.launchDir != filepath.Join(root, "alpha", "sub2") {
+		t.Errorf("expected launchDir alpha/sub2, got %s", pm.launchDir)
+	}
+	if pm.accounts[pm.accountIdx].ID != "test2" {
+		t.Errorf("expected last account test2 preselected, got %s", pm.accounts[pm.accountIdx].ID)
+	}
+
+	// Down past the recent entries reaches create-new-folder, then projects
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	if m.cursor != 0 {
+		t.Errorf("expected create row after the recent entries, got %d", m.cursor)
+	}
+
+	// Filtering hides the section
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("be")})
+	m = updated.(PickerModel)
+	if m.cursor != 1 || strings.Contains(m.View(), "recent") {
+		t.Errorf("expected Recent hidden while filtering (cursor=%d)", m.cursor)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := FormatAge(tt.d); got != tt.want {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestPinnedFavorites(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, cfg := setupTestDirs(t)
+	cfg.Favorites = []string{"alpha/sub2", "missing"}
+
+	m := NewPicker(cfg, nil)
+	if len(m.pinned) != 1 || m.pinned[0].str != "alpha/sub2" {
+		t.Fatalf("expected only the existing favorite pinned, got %+v", m.pinned)
+	}
+	if m.cursor != -1 {
+		t.Errorf("expected cursor on the pinned row, got %d", m.cursor)
+	}
+
+	// Pinned projects survive filtering
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sub")})
+	pm := updated.(PickerModel)
+	if len(pm.pinned) != 1 || len(pm.filtered) != 0 {
+		t.Fatalf("expected pinned match and no list matches, got pinned=%v filtered=%v", pm.pinned, pm.filtered)
+	}
+	if !strings.Contains(pm.View(), "pinned") {
+		t.Error("expected pinned section while filtering")
+	}
+	pm = sendKey(pm, tea.KeyEnter).(PickerModel)
+	if pm.stage != stageAccount || pm.launchDir != filepath.Join(cfg.ProjectsRoot, "alpha", "sub2") {
+		t.Errorf("expected launch in alpha/sub2, got stage=%d dir=%s", pm.stage, pm.launchDir)
+	}
+
+	// Browsing into alpha hides sub2 from the list; ctrl+p on sub1 pins it
+	m = sendKey(m, tea.KeyDown).(PickerModel) // create row
+	m = sendKey(m, tea.KeyDown).(PickerModel) // alpha
+	m = sendKey(m, tea.KeyRight).(PickerModel)
+	if len(m.filtered) != 1 || m.filtered[0] != "sub1" {
+		t.Fatalf("expected pinned sub2 left out of the list, got %v", m.filtered)
+	}
+	if m.cursor != 1 {
+		t.Errorf("expected cursor on sub1 after browsing, got %d", m.cursor)
+	}
+	m = sendKey(m, tea.KeyCtrlP).(PickerModel)
+	if !cfg.IsFavorite("alpha/sub1") {
+		t.Fatalf("expected alpha/sub1 pinned, favorites=%v", cfg.Favorites)
+	}
+	if rel, ok := m.pinnedAtCursor(); !ok || rel != "alpha/sub1" {
+		t.Errorf("expected cursor to follow the new pin, got %q", rel)
+	}
+
+	// ctrl+p on a pinned row unpins it
+	m = sendKey(m, tea.KeyCtrlP).(PickerModel)
+	if cfg.IsFavorite("alpha/sub1") {
+		t.Error("expected alpha/sub1 unpinned")
+	}
+	saved, err := config.Load(config.DefaultConfigPath())
+	if err != nil {
+		t.Fatalf("expected favorites saved: %v", err)
+	}
+	if len(saved.Favorites) != 2 {
+		t.Errorf("expected 2 saved favorites, got %v", saved.Favorites)
+	}
+}
+
+func TestPickerWithFavoriteShortName(t *testing.T) {
+	_, cfg := setupTestDirs(t)
+	cfg.Favorites = []string{"alpha/sub1"}
+
+	m := NewPickerWithProject(cfg, nil, "sub1")
+	if m.preselectedProject != "alpha/sub1" {
+		t.Errorf("expected favorite resolved to alpha/sub1, got %q", m.preselectedProject)
+	}
+	m = NewPickerWithProject(cfg, nil, "beta")
+	if m.preselectedProject != "beta" {
+		t.Errorf("expected folder name kept, got %q", m.preselectedProject)
+	}
+}
+
+func TestPickerMergesRoots(t *testing.T) {
+	root, cfg := setupTestDirs(t)
+	work := t.TempDir()
+	os.MkdirAll(filepath.Join(work, "api"), 0755)
+	os.MkdirAll(filepath.Join(work, "beta"), 0755)
+	cfg.NormalizeRoots()
+	if err := cfg.AddRoot("work", work); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewPicker(cfg, nil)
+	want := []string{"alpha", "api", "beta", "beta"}
+	if strings.Join(m.filtered, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected merged projects %v, got %v", want, m.filtered)
+	}
+	if m.filteredDirs[2] != filepath.Join(root, "beta") || m.filteredDirs[3] != filepath.Join(work, "beta") {
+		t.Errorf("expected primary root's beta first, got %v", m.filteredDirs)
+	}
+	if !strings.Contains(m.View(), "work") {
+		t.Error("expected root column in the list")
+	}
+
+	// tab narrows the list to one root at a time, then back to all
+	m = sendKey(m, tea.KeyTab).(PickerModel)
+	if m.rootFilter == "" || len(m.filtered) != 2 {
+		t.Fatalf("expected first root only, got filter=%q %v", m.rootFilter, m.filtered)
+	}
+	m = sendKey(m, tea.KeyTab).(PickerModel)
+	if m.rootFilter != "work" || strings.Join(m.filtered, ",") != "api,beta" {
+		t.Fatalf("expected work root only, got filter=%q %v", m.rootFilter, m.filtered)
+	}
+	m = sendKey(m, tea.KeyTab).(PickerModel)
+	if m.rootFilter != "" || len(m.filtered) != 4 {
+		t.Fatalf("expected all roots again, got filter=%q %v", m.rootFilter, m.filtered)
+	}
+
+	m = NewPickerWithProject(cfg, nil, "work:beta")
+	if m.preselectedDir != filepath.Join(work, "beta") || m.preselectedProject != "work:beta" {
+		t.Errorf("expected work:beta preselected, got %q (%s)", m.preselectedProject, m.preselectedDir)
+	}
+}
+
+func TestPickerDiscoveryMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	root, cfg := setupTestDirs(t)
+	os.MkdirAll(filepath.Join(root, "alpha", "sub1", ".git"), 0755)
+	os.MkdirAll(filepath.Join(root, "beta", ".git"), 0755)
+	cfg.Discovery.Enabled = true
+
+	// No index yet: the list fills in when the background refresh lands
+	m := NewPicker(cfg, nil)
+	if len(m.filtered) != 0 || !strings.Contains(m.View(), "indexing") {
+		t.Fatalf("expected empty list while indexing, got %v", m.filtered)
+	}
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatal("expected a background index refresh")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(PickerModel)
+	if strings.Join(m.filtered, ",") != "alpha/sub1,beta" {
+		t.Fatalf("expected nested repos listed, got %v", m.filtered)
+	}
+
+	// The next picker starts from the saved index
+	os.MkdirAll(filepath.Join(root, "gamma", ".git"), 0755)
+	m = NewPicker(cfg, nil)
+	if strings.Join(m.filtered, ",") != "alpha/sub1,beta" {
+		t.Fatalf("expected cached projects before the refresh, got %v", m.filtered)
+	}
+	m = sendKey(m, tea.KeyDown).(PickerModel) // beta
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	updated, _ = m.Update(m.Init()())
+	m = updated.(PickerModel)
+	if strings.Join(m.filtered, ",") != "alpha/sub1,beta,gamma" {
+		t.Fatalf("expected refreshed projects, got %v", m.filtered)
+	}
+	if m.cursor != 2 {
+		t.Errorf("expected cursor kept on beta, got %d", m.cursor)
+	}
+}
+
+func TestPromptStep(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, cfg := setupTestDirs(t)
+	cfg.Accounts[0].PromptArgs = []string{"-i", config.PromptPlaceholder}
+
+	m := NewPicker(cfg, nil)
+	m = sendKey(m, tea.KeyEnter).(PickerModel) // alpha
+	if m.stage != stageAccount {
+		t.Fatalf("expected account stage, got %d", m.stage)
+	}
+
+	// The second account has no mapping: p says so instead of opening
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(PickerModel)
+	if m.stage != stageAccount || !strings.Contains(m.accountErr, "doesn't support an initial prompt") {
+		t.Fatalf("expected an unsupported-prompt error, got stage=%d err=%q", m.stage, m.accountErr)
+	}
+
+	m = sendKey(m, tea.KeyUp).(PickerModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(PickerModel)
+	if m.stage != stagePrompt {
+		t.Fatalf("expected prompt stage, got %d", m.stage)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("fix\nthe tests")})
+	m = updated.(PickerModel)
+	if m.promptInput != "fix the tests" || !strings.Contains(m.View(), "fix the tests") {
+		t.Errorf("unexpected prompt input %q", m.promptInput)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PickerModel)
+	if cmd == nil || m.prompt != "fix the tests" || m.accountErr != "" {
+		t.Errorf("expected launch with the prompt, got prompt=%q err=%q", m.prompt, m.accountErr)
+	}
+}
+
+func TestPromptFlagWithUnsupportedAccount(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, cfg := setupTestDirs(t)
+	cfg.Accounts = cfg.Accounts[:1]
+
+	// A single account launches straight away, unless it can't take the prompt
+	m := NewPicker(cfg, nil).WithPrompt("  add tests ")
+	m = sendKey(m, tea.KeyEnter).(PickerModel)
+	if m.stage != stageAccount || !strings.Contains(m.accountErr, "Test doesn't support an initial prompt") {
+		t.Errorf("expected the prompt not to be dropped silently, got stage=%d err=%q", m.stage, m.accountErr)
+	}
+	if !strings.Contains(m.View(), "add tests") {
+		t.Error("expected the prompt shown on the account stage")
+	}
+}
+
+func TestPickerPathOnly(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	root, cfg := setupTestDirs(t)
+	cfg.Accounts = nil // path-only mode never needs a tool
+
+	m := NewPicker(cfg, nil).WithPathOnly().WithFilter("bet")
+	if len(m.filtered) != 1 || m.filtered[0] != "beta" {
+		t.Fatalf("expected the filter to narrow to beta, got %v", m.filtered)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PickerModel)
+	if cmd == nil || m.stage != stageProject {
+		t.Fatalf("expected the picker to quit at the project stage, got stage=%d", m.stage)
+	}
+	if m.PickedDir() != filepath.Join(root, "beta") {
+		t.Errorf("expected beta picked, got %q", m.PickedDir())
+	}
+
+	m = NewPicker(cfg, nil).WithPathOnly()
+	m = sendKey(m, tea.KeyEsc).(PickerModel)
+	if m.PickedDir() != "" {
+		t.Errorf("expected nothing picked on cancel, got %q", m.PickedDir())
+	}
+}
+
+func TestCreateFromTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	root, cfg := setupTestDirs(t)
+	cfg.Accounts = cfg.Accounts[:1]
+	cfg.Templates = []config.Template{
+		{Name: "agents", Files: map[string]string{"AGENTS.md": "# {{name}}\n"}},
+	}
+
+	m := NewPicker(cfg, nil)
+	m.cursor = 0
+	m = sendKey(m, tea.KeyEnter).(PickerModel)
+	if m.stage != stageCreate || !strings.Contains(m.View(), "empty folder") {
+		t.Fatalf("expected the create stage listing templates, got %d", m.stage)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gamma")})
+	m = sendKey(updated, tea.KeyDown).(PickerModel)
+	if m.templateIdx != 1 {
+		t.Fatalf("expected the template selected, got %d", m.templateIdx)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PickerModel)
+	if !m.creating || cmd == nil {
+		t.Fatal("expected the template to be applied in the background")
+	}
+	updated, cmd = m.Update(cmd())
+	m = updated.(PickerModel)
+	if m.creating || m.createErr != "" || cmd == nil {
+		t.Fatalf("expected a launch after creating, got err=%q", m.createErr)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "gamma", "AGENTS.md"))
+	if err != nil || string(data) != "# gamma\n" {
+		t.Errorf("expected AGENTS.md seeded from the template, got %q, %v", data, err)
+	}
+}
+
+func TestAccountStageShowsInjectedEnv(t *testing.T) {
+	root, cfg := setupTestDirs(t)
+	if err := os.WriteFile(filepath.Join(root, "alpha", ".qs.env"), []byte("OPENAI_API_KEY=sk-client-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Projects = []config.ProjectSettings{{
+		Project:  "alpha",
+		EnvFiles: []string{".qs.env"},
+		Env:      map[string]string{"DATABASE_URL": "postgres://localhost/alpha"},
+	}}
+
+	pm := NewPicker(cfg, nil)
+	pm.keys = config.AccountKeys{"test": {"ANTHROPIC_API_KEY": "sk-account-secret"}}
+	pm = sendKey(pm, tea.KeyEnter).(PickerModel)
+	if pm.stage != stageAccount {
+		t.Fatalf("expected account stage, got %d", pm.stage)
+	}
+	view := pm.View()
+	for _, want := range []string{"ANTHROPIC_API_KEY", "DATABASE_URL (config)", "OPENAI_API_KEY (.qs.env)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("account view missing %q:\n%s", want, view)
+		}
+	}
+	for _, secret := range []string{"sk-client-secret", "sk-account-secret", "postgres://"} {
+		if strings.Contains(view, secret) {
+			t.Errorf("account view shows a value %q", secret)
+		}
+	}
+
+	// The second account has no keys of its own, only the project's env
+	pm = sendKey(pm, tea.KeyDown).(PickerModel)
+	if view := pm.View(); strings.Contains(view, "ANTHROPIC_API_KEY") || !strings.Contains(view, "DATABASE_URL") {
+		t.Errorf("unexpected env for the second account:\n%s", view)
+	}
+}
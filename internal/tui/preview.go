@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bcmister/qs/internal/worktree"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The preview pane appears beside the project list once the terminal is
+// at least minPreviewWidth columns wide.
+const (
+	minPreviewWidth     = 100
+	previewReadmeLines  = 8
+	previewCommits      = 5
+	previewLanguages    = 4
+	previewMaxFiles     = 2000 // files sampled for language detection
+	previewScanMaxDepth = 3
+)
+
+// readmeNames are tried in order for the README excerpt.
+var readmeNames = []string{"README.md", "README", "README.markdown", "README.rst", "README.txt", "readme.md"}
+
+// agentFiles are the instruction files coding agents read from a project.
+var agentFiles = []string{"CLAUDE.md", "AGENTS.md", "GEMINI.md"}
+
+// languageByExt maps source file extensions to language names.
+var languageByExt = map[string]string{
+	".go": "Go", ".rs": "Rust", ".py": "Python", ".rb": "Ruby",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript",
+	".java": "Java", ".kt": "Kotlin", ".scala": "Scala", ".swift": "Swift",
+	".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".hpp": "C++", ".cs": "C#",
+	".php": "PHP", ".lua": "Lua", ".zig": "Zig", ".ex": "Elixir", ".exs": "Elixir",
+	".dart": "Dart", ".vue": "Vue", ".svelte": "Svelte", ".sh": "Shell", ".ps1": "PowerShell",
+	".html": "HTML", ".css": "CSS", ".scss": "CSS",
+}
+
+// skipPreviewDirs are never scanned for languages: dependencies and build
+// output say little about the project itself.
+var skipPreviewDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "target": true, "dist": true, "build": true,
+}
+
+// projectPreview is what the preview pane shows for one directory.
+type projectPreview struct {
+	readme     []string
+	commits    []worktree.Commit
+	languages  []string
+	agentFiles []string
+}
+
+// previewMsg delivers a loaded preview.
+type previewMsg struct {
+	dir     string
+	preview projectPreview
+}
+
+// fetchPreview loads dir's preview off the UI goroutine.
+func fetchPreview(dir string) tea.Cmd {
+	return func() tea.Msg {
+		return previewMsg{dir: dir, preview: loadPreview(dir)}
+	}
+}
+
+// loadPreview reads a project's README excerpt, recent commits, languages
+// and agent instruction files.
+func loadPreview(dir string) projectPreview {
+	var p projectPreview
+	for _, name := range readmeNames {
+		if lines, ok := readLines(filepath.Join(dir, name), previewReadmeLines); ok {
+			p.readme = lines
+			break
+		}
+	}
+	// Only repositories have a log; a plain folder under a versioned root
+	// would otherwise show the root's commits
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		p.commits, _ = worktree.Log(dir, previewCommits)
+		for i := range p.commits {
+			p.commits[i].Subject = printable(p.commits[i].Subject)
+		}
+	}
+	p.languages = detectLanguages(dir)
+	for _, name := range agentFiles {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+			p.agentFiles = append(p.agentFiles, name)
+		}
+	}
+	return p
+}
+
+// readLines returns up to n non-blank lines of a file, made printable.
+func readLines(path string, n int) ([]string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() && len(lines) < n {
+		line := printable(strings.ReplaceAll(sc.Text(), "\t", "    "))
+		if line = strings.TrimRight(line, " "); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, true
+}
+
+// printable strips escape sequences and other control characters from text
+// read out of a project, so a README can't drive the user's terminal.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, ansi.Strip(s))
+}
+
+// detectLanguages counts source files by extension in the top levels of
+// dir and returns the most common languages first.
+func detectLanguages(dir string) []string {
+	counts := make(map[string]int)
+	files := 0
+	var walk func(string, int)
+	walk = func(d string, depth int) {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if files >= previewMaxFiles {
+				return
+			}
+			name := e.Name()
+			if strings.HasPrefix(name, ".") {
+				continue
+			}
+			if e.IsDir() {
+				if depth < previewScanMaxDepth && !skipPreviewDirs[name] {
+					walk(filepath.Join(d, name), depth+1)
+				}
+				continue
+			}
+			files++
+			if lang, ok := languageByExt[strings.ToLower(filepath.Ext(name))]; ok {
+				counts[lang]++
+			}
+		}
+	}
+	walk(dir, 1)
+
+	langs := make([]string, 0, len(counts))
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if counts[langs[i]] != counts[langs[j]] {
+			return counts[langs[i]] > counts[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	if len(langs) > previewLanguages {
+		langs = langs[:previewLanguages]
+	}
+	return langs
+}
+
+// previewDir returns the directory under the cursor, or "" on the
+// create-new-folder row.
+func (m PickerModel) previewDir() string {
+	if ref, ok := m.pinnedAtCursor(); ok {
+		return m.cfg.RefDir(ref)
+	}
+	if rp, ok := m.recentAtCursor(); ok {
+		return rp.Path
+	}
+	if m.cursor > 0 && m.cursor <= len(m.filteredDirs) {
+		return m.filteredDirs[m.cursor-1]
+	}
+	return ""
+}
+
+// previewVisible reports whether the preview pane is on and fits.
+func (m PickerModel) previewVisible() bool {
+	return m.showPreview && m.width >= minPreviewWidth
+}
+
+// loadPreview starts loading the highlighted project's preview if the pane
+// is showing and it isn't cached yet.
+func (m PickerModel) loadPreview() tea.Cmd {
+	if m.stage != stageProject || !m.previewVisible() {
+		return nil
+	}
+	dir := m.previewDir()
+	if dir == "" {
+		return nil
+	}
+	if _, done := m.previews[dir]; done || m.previewPending[dir] {
+		return nil
+	}
+	m.previewPending[dir] = true
+	return fetchPreview(dir)
+}
+
+// viewPreview renders the preview pane for the highlighted project.
+func (m PickerModel) viewPreview(width, height int) string {
+	title := lipgloss.NewStyle().Foreground(ColorBrCyan)
+	dim := lipgloss.NewStyle().Foreground(ColorDimGray)
+	white := lipgloss.NewStyle().Foreground(ColorWhite)
+	green := lipgloss.NewStyle().Foreground(ColorGreen)
+
+	var s strings.Builder
+	dir := m.previewDir()
+	p, loaded := m.previews[dir]
+	switch {
+	case dir == "":
+		s.WriteString(dim.Render("new folder in "+m.createDir()) + "\n")
+	case !loaded:
+		s.WriteString(white.Render(m.cfg.ProjectRef(dir)) + "\n\n")
+		s.WriteString(dim.Render("loading...") + "\n")
+	default:
+		s.WriteString(white.Render(m.cfg.ProjectRef(dir)) + "\n")
+		s.WriteString(dim.Render(dir) + "\n\n")
+
+		langs := "-"
+		if len(p.languages) > 0 {
+			langs = strings.Join(p.languages, ", ")
+		}
+		s.WriteString(fmt.Sprintf("%s %s\n", dim.Render("languages"), white.Render(langs)))
+
+		var agents []string
+		for _, name := range agentFiles {
+			if containsString(p.agentFiles, name) {
+				agents = append(agents, green.Render("✓ "+name))
+			} else {
+				agents = append(agents, dim.Render("- "+name))
+			}
+		}
+		s.WriteString(fmt.Sprintf("%s    %s\n", dim.Render("agents"), strings.Join(agents, " ")))
+
+		if len(p.commits) > 0 {
+			s.WriteString("\n" + title.Render("commits") + "\n")
+			now := time.Now()
+			for _, c := range p.commits {
+				s.WriteString(fmt.Sprintf("%s %s %s\n", dim.Render(c.Hash), white.Render(c.Subject), dim.Render(FormatAge(now.Sub(c.Time)))))
+			}
+		}
+
+		if len(p.readme) > 0 {
+			s.WriteString("\n" + title.Render("readme") + "\n")
+			for _, line := range p.readme {
+				s.WriteString(dim.Render(line) + "\n")
+			}
+		}
+	}
+
+	return lipgloss.NewStyle().
+		MaxWidth(width).
+		MaxHeight(height).
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(ColorDimGray).
+		PaddingLeft(1).
+		Render(strings.TrimRight(s.String(), "\n"))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
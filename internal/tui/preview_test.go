@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadPreview(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Alpha\n\nDoes alpha things.\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("rules\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.go"), nil, 0644)
+	os.MkdirAll(filepath.Join(dir, "cmd", "tool"), 0755)
+	os.WriteFile(filepath.Join(dir, "cmd", "tool", "main.go"), nil, 0644)
+	os.WriteFile(filepath.Join(dir, "run.sh"), nil, 0644)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "dep"), 0755)
+	for _, f := range []string{"a.js", "b.js", "c.js"} {
+		os.WriteFile(filepath.Join(dir, "node_modules", "dep", f), nil, 0644)
+	}
+
+	p := loadPreview(dir)
+	if strings.Join(p.readme, "|") != "# Alpha|Does alpha things." {
+		t.Errorf("unexpected readme excerpt %q", p.readme)
+	}
+	if strings.Join(p.languages, ",") != "Go,Shell" {
+		t.Errorf("expected Go then Shell with node_modules skipped, got %v", p.languages)
+	}
+	if strings.Join(p.agentFiles, ",") != "CLAUDE.md" {
+		t.Errorf("unexpected agent files %v", p.agentFiles)
+	}
+	if len(p.commits) != 0 {
+		t.Errorf("expected no commits outside a repository, got %v", p.commits)
+	}
+}
+
+func TestReadLinesStripsControlCharacters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	data := "\x1b[2J\x1b]0;pwned\x07# Title\x1b[31m red\x1b[0m\n\x1b[8m\x1b[0m\nbell\x07 and\ttab\r\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lines, _ := readLines(path, 5)
+	if got := strings.Join(lines, "|"); got != "# Title red|bell and    tab" {
+		t.Errorf("readLines = %q", got)
+	}
+}
+
+func TestPickerPreviewPane(t *testing.T) {
+	root, cfg := setupTestDirs(t)
+	alpha := filepath.Join(root, "alpha")
+	os.WriteFile(filepath.Join(alpha, "README.md"), []byte("Alpha readme line\n"), 0644)
+
+	m := NewPicker(cfg, nil) // cursor on alpha
+	if m.loadPreview() != nil {
+		t.Fatal("expected no preview on a narrow terminal")
+	}
+
+	updated, cmd := m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	m = updated.(PickerModel)
+	if cmd == nil || !m.previewPending[alpha] {
+		t.Fatal("expected the highlighted project's preview to load")
+	}
+	if !strings.Contains(m.View(), "loading...") {
+		t.Error("expected a loading placeholder")
+	}
+
+	updated, _ = m.Update(fetchPreview(alpha)())
+	m = updated.(PickerModel)
+	view := m.View()
+	for _, want := range []string{"Alpha readme line", "CLAUDE.md", "ctrl+t"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the preview:\n%s", want, view)
+		}
+	}
+
+	// Cached: moving back onto alpha doesn't load it again
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	m = sendKey(m, tea.KeyUp).(PickerModel)
+	if m.loadPreview() != nil {
+		t.Error("expected the cached preview reused")
+	}
+
+	m = sendKey(m, tea.KeyCtrlT).(PickerModel)
+	if strings.Contains(m.View(), "Alpha readme line") {
+		t.Error("expected ctrl+t to hide the preview")
+	}
+}
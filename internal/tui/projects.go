@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/discover"
+)
+
+// Project is one entry in the merged project list the picker shows at the
+// top level.
+type Project struct {
+	Name string // path relative to its root, slash-separated
+	Dir  string
+	Root string // root name
+}
+
+// ListProjects returns the projects across all roots, sorted by name: the
+// root folders, or with discovery enabled the indexed repositories. A
+// missing or stale index is refreshed first, since there is no picker to
+// show it updating.
+func ListProjects(cfg *config.Config) []Project {
+	var discovered []discover.Project
+	if cfg.Discovery.Enabled {
+		idx, _ := discover.LoadIndex("")
+		if idx == nil {
+			idx = &discover.Index{}
+		}
+		roots := discoverRoots(cfg)
+		var ok bool
+		if discovered, ok = idx.Cached(roots); !ok {
+			discovered = idx.Refresh(roots, discover.Options{Depth: cfg.Discovery.Depth, Markers: cfg.Discovery.Markers})
+			_ = discover.SaveIndex(idx, "")
+		}
+	}
+	return mergeProjects(cfg, discovered, "")
+}
+
+// MatchProjects returns the projects whose name fuzzy-matches query, best
+// match first, as the picker's filter ranks them.
+func MatchProjects(query string, projects []Project) []Project {
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+	var matches []Project
+	for _, r := range fuzzyFilter(query, names) {
+		matches = append(matches, projects[r.index])
+	}
+	return matches
+}
+
+// mergeProjects lists the projects in every root, or only in rootFilter
+// when set. With discovery enabled the discovered projects are used
+// instead of each root's folders. Roots are already sorted, so ties in name
+// keep root order.
+func mergeProjects(cfg *config.Config, discovered []discover.Project, rootFilter string) []Project {
+	var projects []Project
+	if cfg.Discovery.Enabled {
+		for _, p := range discovered {
+			if rootFilter == "" || p.Root == rootFilter {
+				projects = append(projects, Project{Name: p.Rel, Dir: p.Path, Root: p.Root})
+			}
+		}
+	} else {
+		for _, r := range cfg.ProjectRoots() {
+			if rootFilter != "" && r.Name != rootFilter {
+				continue
+			}
+			for _, name := range scanProjects(r.Path) {
+				projects = append(projects, Project{Name: name, Dir: filepath.Join(r.Path, name), Root: r.Name})
+			}
+		}
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
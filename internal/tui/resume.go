@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxResumeRows caps how many sessions the resume list shows at once.
+const maxResumeRows = 12
+
+// sessionsMsg delivers the stored sessions for a launch directory.
+type sessionsMsg struct {
+	dir      string
+	sessions []session.Session
+	err      error
+}
+
+// startResume lists account's stored sessions for the launch directory, or
+// explains that qs can't read its history.
+func (m PickerModel) startResume(account config.Account) (tea.Model, tea.Cmd) {
+	reader, ok := session.ReaderFor(account.Command)
+	if !ok {
+		m.accountErr = fmt.Sprintf("%s has no session history qs can read", account.Label)
+		return m, nil
+	}
+	m.stage = stageResume
+	m.sessions = nil
+	m.sessionIdx = 0
+	m.sessionsLoaded = false
+	m.sessionErr = ""
+	m.accountErr = ""
+
+	dir := m.launchDir
+	cfg, keys, accountID := m.cfg, m.keys, account.ID
+	return m, func() tea.Msg {
+		vars, err := config.LaunchEnv(cfg, keys, accountID, dir)
+		if err != nil {
+			return sessionsMsg{dir: dir, err: err}
+		}
+		env, err := config.ResolveEnv(cfg, vars)
+		if err != nil {
+			return sessionsMsg{dir: dir, err: err}
+		}
+		sessions, err := reader.List(dir, env)
+		return sessionsMsg{dir: dir, sessions: sessions, err: err}
+	}
+}
+
+func (m PickerModel) updateResume(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.stage = stageAccount
+		return m, nil
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEnter:
+		if m.sessionIdx < len(m.sessions) {
+			return m.resumeSession(m.accounts[m.accountIdx], m.sessions[m.sessionIdx])
+		}
+	case tea.KeyUp:
+		if m.sessionIdx > 0 {
+			m.sessionIdx--
+		}
+	case tea.KeyDown:
+		if m.sessionIdx < len(m.sessions)-1 {
+			m.sessionIdx++
+		}
+	}
+	return m, nil
+}
+
+// resumeSession relaunches account on session s. Resuming always happens
+// in the project itself: the session belongs to it, not to a new worktree.
+func (m PickerModel) resumeSession(account config.Account, s session.Session) (tea.Model, tea.Cmd) {
+	reader, ok := session.ReaderFor(account.Command)
+	if !ok {
+		m.stage = stageAccount
+		return m, nil
+	}
+	return m.launch(account, reader.ResumeArgs(s.ID, account.ResolvedArgs()), false)
+}
+
+func (m PickerModel) viewResume() string {
+	var s strings.Builder
+
+	title := lipgloss.NewStyle().Foreground(ColorBrCyan)
+	dim := lipgloss.NewStyle().Foreground(ColorDimGray)
+	white := lipgloss.NewStyle().Foreground(ColorWhite)
+	sel := lipgloss.NewStyle().Foreground(ColorBrCyan).Bold(true)
+
+	account := m.accounts[m.accountIdx]
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf(" %s %s\n", title.Render("qs"), dim.Render("- "+m.cfg.ProjectRef(m.launchDir)+" - resume "+account.Label)))
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("  %s\n", dim.Render("---------------------------------")))
+
+	switch {
+	case !m.sessionsLoaded:
+		s.WriteString(fmt.Sprintf("  %s\n", dim.Render("reading sessions...")))
+	case m.sessionErr != "":
+		errStyle := lipgloss.NewStyle().Foreground(ColorRed)
+		s.WriteString(fmt.Sprintf("  %s\n", errStyle.Render(m.sessionErr)))
+	case len(m.sessions) == 0:
+		s.WriteString(fmt.Sprintf("  %s\n", dim.Render("no sessions for this project")))
+	}
+
+	// Keep the cursor in a window of maxResumeRows
+	start := 0
+	if m.sessionIdx >= maxResumeRows {
+		start = m.sessionIdx - maxResumeRows + 1
+	}
+	end := min(start+maxResumeRows, len(m.sessions))
+	now := time.Now()
+	for i := start; i < end; i++ {
+		sess := m.sessions[i]
+		first := sess.FirstMessage
+		if first == "" {
+			first = "(no message)"
+		}
+		meta := dim.Render(fmt.Sprintf("%s  %d msgs", FormatAge(now.Sub(sess.Updated)), sess.Messages))
+		date := sess.Updated.Local().Format("Jan 02 15:04")
+		if i == m.sessionIdx {
+			s.WriteString(fmt.Sprintf("  %s %s  %s  %s\n", sel.Render(">"), dim.Render(date), white.Render(truncate(first, 50)), meta))
+		} else {
+			s.WriteString(fmt.Sprintf("    %s  %s  %s\n", dim.Render(date), dim.Render(truncate(first, 50)), meta))
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(fmt.Sprintf("  %s navigate  %s resume  %s back\n", dim.Render("up/down"), dim.Render("enter"), dim.Render("esc")))
+	return s.String()
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bcmister/qs/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeReader serves fixed sessions for any directory.
+type fakeReader struct {
+	sessions []session.Session
+}
+
+func (r fakeReader) List(dir string, env map[string]string) ([]session.Session, error) {
+	return r.sessions, nil
+}
+
+func (fakeReader) ResumeArgs(id string, args []string) []string {
+	return append(args, "--resume", id)
+}
+
+func TestResumeStage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	_, cfg := setupTestDirs(t)
+	cfg.Accounts[0].Command = "qs-fake-agent"
+	now := time.Now()
+	session.Register("qs-fake-agent", fakeReader{sessions: []session.Session{
+		{ID: "s1", Updated: now.Add(-time.Hour), FirstMessage: "fix the login bug", Messages: 4},
+		{ID: "s2", Updated: now.Add(-48 * time.Hour), FirstMessage: "add tests", Messages: 9},
+	}})
+
+	m := NewPicker(cfg, nil)
+	m = sendKey(m, tea.KeyEnter).(PickerModel) // alpha
+
+	// The second account's command has no reader
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(PickerModel)
+	if m.stage != stageAccount || !strings.Contains(m.accountErr, "no session history") {
+		t.Fatalf("expected a no-reader error, got stage=%d err=%q", m.stage, m.accountErr)
+	}
+
+	m = sendKey(m, tea.KeyUp).(PickerModel)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(PickerModel)
+	if m.stage != stageResume || cmd == nil {
+		t.Fatalf("expected resume stage with a load command, got %d", m.stage)
+	}
+	if !strings.Contains(m.View(), "reading sessions...") {
+		t.Error("expected a loading hint")
+	}
+
+	updated, _ = m.Update(cmd())
+	m = updated.(PickerModel)
+	view := m.View()
+	if !strings.Contains(view, "fix the login bug") || !strings.Contains(view, "9 msgs") {
+		t.Errorf("expected both sessions listed, got:\n%s", view)
+	}
+
+	m = sendKey(m, tea.KeyDown).(PickerModel)
+	if m.sessionIdx != 1 {
+		t.Errorf("expected cursor on the second session, got %d", m.sessionIdx)
+	}
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(PickerModel)
+	if cmd == nil || m.accountErr != "" {
+		t.Errorf("expected a launch, got err=%q", m.accountErr)
+	}
+
+	m.stage = stageResume
+	m = sendKey(m, tea.KeyEsc).(PickerModel)
+	if m.stage != stageAccount {
+		t.Errorf("expected esc back to the account stage, got %d", m.stage)
+	}
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rootsEditor edits the list of project roots for the first-run flow and
+// the setup wizard: a path input above the roots added so far. tab adds the
+// typed path, up/down select a root and ctrl+x removes it. Enter and Esc are
+// left to the owning model.
+type rootsEditor struct {
+	roots []config.ProjectRoot
+	idx   int
+	input textinput.Model
+	err   string
+}
+
+func newRootsEditor(roots []config.ProjectRoot, value string) rootsEditor {
+	input := textinput.New()
+	input.Placeholder = config.DefaultProjectsRoot()
+	input.Width = 56
+	input.CharLimit = 512
+	input.SetValue(value)
+
+	return rootsEditor{
+		roots: append([]config.ProjectRoot(nil), roots...),
+		input: input,
+	}
+}
+
+func (e rootsEditor) update(msg tea.KeyMsg) (rootsEditor, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyTab:
+		e.addInput()
+		return e, nil
+	case tea.KeyUp:
+		if e.idx > 0 {
+			e.idx--
+		}
+		return e, nil
+	case tea.KeyDown:
+		if e.idx < len(e.roots)-1 {
+			e.idx++
+		}
+		return e, nil
+	case tea.KeyCtrlX:
+		if len(e.roots) > 0 {
+			e.roots = append(e.roots[:e.idx], e.roots[e.idx+1:]...)
+			if e.idx >= len(e.roots) && e.idx > 0 {
+				e.idx--
+			}
+			e.err = ""
+		}
+		return e, nil
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	e.err = ""
+	return e, cmd
+}
+
+// addInput adds the typed path as a new root and clears the input. It
+// returns false, with err set, if the path can't be added. An empty input
+// is a no-op.
+func (e *rootsEditor) addInput() bool {
+	path := strings.TrimSpace(e.input.Value())
+	if path == "" {
+		return true
+	}
+	cfg := &config.Config{Roots: e.roots}
+	if err := cfg.AddRoot("", path); err != nil {
+		e.err = err.Error()
+		return false
+	}
+	e.roots = cfg.Roots
+	e.idx = len(e.roots) - 1
+	e.input.SetValue("")
+	e.err = ""
+	return true
+}
+
+// finish adds any typed path and returns the roots, or false if there are
+// none or the typed path is invalid.
+func (e *rootsEditor) finish() ([]config.ProjectRoot, bool) {
+	if !e.addInput() {
+		return nil, false
+	}
+	if len(e.roots) == 0 {
+		e.err = "Add at least one project path."
+		return nil, false
+	}
+	return e.roots, true
+}
+
+func (e rootsEditor) view() string {
+	var s strings.Builder
+	s.WriteString("  " + e.input.View() + "\n")
+	if path := strings.TrimSpace(e.input.Value()); path != "" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			s.WriteString("  " + WarningStyle.Render("Directory does not exist — will be created") + "\n")
+		} else {
+			s.WriteString("  " + SuccessStyle.Render("✓ Directory exists") + "\n")
+		}
+	}
+
+	if len(e.roots) > 0 {
+		s.WriteString("\n")
+		width := 0
+		for _, r := range e.roots {
+			if len(r.Name) > width {
+				width = len(r.Name)
+			}
+		}
+		for i, r := range e.roots {
+			label := fmt.Sprintf("%-*s", width, r.Name)
+			primary := ""
+			if i == 0 {
+				primary = DimStyle.Render("  (primary)")
+			}
+			if i == e.idx {
+				s.WriteString(fmt.Sprintf("  %s %s  %s%s\n", TitleStyle.Render(">"), WhiteStyle.Render(label), DimStyle.Render(r.Path), primary))
+			} else {
+				s.WriteString(fmt.Sprintf("    %s  %s%s\n", DimStyle.Render(label), DimStyle.Render(r.Path), primary))
+			}
+		}
+	}
+
+	if e.err != "" {
+		s.WriteString("\n  " + ErrorStyle.Render(e.err) + "\n")
+	}
+	return s.String()
+}
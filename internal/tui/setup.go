@@ -34,14 +34,24 @@ type SetupModel struct {
 	height       int
 	err          error
 
-	// Step 1: Projects root
-	rootInput    textinput.Model
+	// Step 1: Projects roots
+	roots        rootsEditor
 	projectsRoot string
+	projectRoots []config.ProjectRoot
 
 	// Step 2: Monitors
-	monitors     []monitor.Monitor
-	monitorIdx   int
-	windowCounts []int
+	monitors       []monitor.Monitor
+	monitorIdx     int
+	windowCounts   []int
+	loadedMonitors []config.MonitorConfig // a loaded profile's arrangement, whose windows replace the config's
+
+	// Step 2 sub-view: named profiles
+	profiles      []config.Profile
+	profileMode   bool
+	profileIdx    int
+	profileAction string // "", "new" or "rename"
+	profileInput  textinput.Model
+	profileMsg    string
 
 	// Step 3: Accounts
 	accounts     []config.Account
@@ -82,19 +92,14 @@ type setupKeyEntry struct {
 	value string
 }
 
-// NewSetup creates a new setup wizard model
-func NewSetup(existingCfg *config.Config) SetupModel {
-	// Projects root input
-	rootInput := textinput.New()
-	rootInput.Placeholder = config.DefaultProjectsRoot()
-	rootInput.CharLimit = 256
-	rootInput.Width = 50
-
-	defaultRoot := config.DefaultProjectsRoot()
-	if existingCfg != nil && existingCfg.ProjectsRoot != "" {
-		defaultRoot = existingCfg.ProjectsRoot
+// NewSetup creates a new setup wizard model. keys must be the loaded keys
+// file, since the wizard saves it back.
+func NewSetup(existingCfg *config.Config, keys config.AccountKeys) SetupModel {
+	// Projects roots: edit the existing ones, or start from the default
+	roots := newRootsEditor(nil, config.DefaultProjectsRoot())
+	if existingCfg != nil && len(existingCfg.ProjectRoots()) > 0 {
+		roots = newRootsEditor(existingCfg.ProjectRoots(), "")
 	}
-	rootInput.SetValue(defaultRoot)
 
 	// Copy default accounts
 	accounts := make([]config.Account, len(config.DefaultAccounts))
@@ -109,6 +114,7 @@ func NewSetup(existingCfg *config.Config) SetupModel {
 			Icon:       a.Icon,
 			Enabled:    a.Enabled,
 			AuthUser:   a.AuthUser,
+			PromptArgs: a.PromptArgs.Clone(),
 		}
 	}
 
@@ -126,17 +132,25 @@ func NewSetup(existingCfg *config.Config) SetupModel {
 				Icon:       a.Icon,
 				Enabled:    a.Enabled,
 				AuthUser:   a.AuthUser,
+				PromptArgs: a.PromptArgs.Clone(),
+				Env:        a.Env.Clone(),
 			}
 		}
 	}
 
-	keys, _ := config.LoadKeys()
+	var profiles []config.Profile
+	if existingCfg != nil {
+		for _, p := range existingCfg.Profiles {
+			profiles = append(profiles, config.Profile{Name: p.Name, Monitors: config.CopyMonitors(p.Monitors)})
+		}
+	}
 
 	return SetupModel{
 		existingCfg: existingCfg,
 		step:        stepWelcome,
-		rootInput:   rootInput,
+		roots:       roots,
 		accounts:    accounts,
+		profiles:    profiles,
 		keys:        keys,
 		keysMode:    "select",
 	}
@@ -161,8 +175,7 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			accountID := msg.accountID
 			a := config.AccountByID(m.accounts, accountID)
 			if a != nil {
-				env := accountEnvSlice(m.keys, accountID)
-				return m, probeAuthCmd(accountID, a.Command, env)
+				return m, probeAuthCmd(m.existingCfg, m.keys, *a)
 			}
 			m.authMessage = "Auth completed successfully"
 		}
@@ -227,6 +240,9 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case stepProjectsRoot:
 			return m.updateProjectsRoot(msg)
 		case stepMonitors:
+			if m.profileMode {
+				return m.updateProfiles(msg)
+			}
 			return m.updateMonitors(msg)
 		case stepAccounts:
 			return m.updateAccounts(msg)
@@ -242,7 +258,7 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Pass to text input if active
 	if m.step == stepProjectsRoot {
 		var cmd tea.Cmd
-		m.rootInput, cmd = m.rootInput.Update(msg)
+		m.roots.input, cmd = m.roots.input.Update(msg)
 		return m, cmd
 	}
 
@@ -269,8 +285,7 @@ func (m SetupModel) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, DefaultKeyMap.Enter):
 		m.step = stepProjectsRoot
-		m.rootInput.Focus()
-		return m, textinput.Blink
+		return m, m.roots.input.Focus()
 	case key.Matches(msg, DefaultKeyMap.Quit):
 		return m, tea.Quit
 	}
@@ -280,14 +295,21 @@ func (m SetupModel) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m SetupModel) updateProjectsRoot(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, DefaultKeyMap.Enter):
-		m.projectsRoot = m.rootInput.Value()
-		if m.projectsRoot == "" {
-			m.projectsRoot = config.DefaultProjectsRoot()
+		if len(m.roots.roots) == 0 && strings.TrimSpace(m.roots.input.Value()) == "" {
+			m.roots.input.SetValue(config.DefaultProjectsRoot())
+		}
+		roots, ok := m.roots.finish()
+		if !ok {
+			return m, nil
 		}
+		m.projectRoots = roots
+		m.projectsRoot = roots[0].Path
 
-		// Create directory if it doesn't exist
-		if _, err := os.Stat(m.projectsRoot); os.IsNotExist(err) {
-			os.MkdirAll(m.projectsRoot, 0755)
+		// Create directories if they don't exist
+		for _, r := range roots {
+			if _, err := os.Stat(r.Path); os.IsNotExist(err) {
+				os.MkdirAll(r.Path, 0755)
+			}
 		}
 
 		// Detect monitors and move to that step
@@ -295,11 +317,11 @@ func (m SetupModel) updateProjectsRoot(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, detectMonitors
 	case key.Matches(msg, DefaultKeyMap.Escape):
 		m.step = stepWelcome
-		m.rootInput.Blur()
+		m.roots.input.Blur()
 		return m, nil
 	default:
 		var cmd tea.Cmd
-		m.rootInput, cmd = m.rootInput.Update(msg)
+		m.roots, cmd = m.roots.update(msg)
 		return m, cmd
 	}
 }
@@ -311,8 +333,7 @@ func (m SetupModel) updateMonitors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case key.Matches(msg, DefaultKeyMap.Escape):
 		m.step = stepProjectsRoot
-		m.rootInput.Focus()
-		return m, textinput.Blink
+		return m, m.roots.input.Focus()
 	case msg.String() == "left", msg.String() == "h":
 		if m.monitorIdx > 0 {
 			m.monitorIdx--
@@ -329,10 +350,115 @@ func (m SetupModel) updateMonitors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.monitors) > 0 && m.windowCounts[m.monitorIdx] > 1 {
 			m.windowCounts[m.monitorIdx]--
 		}
+	case msg.String() == "p":
+		m.profileMode = true
+		m.profileMsg = ""
+		if m.profileIdx >= len(m.profiles) {
+			m.profileIdx = 0
+		}
+	}
+	return m, nil
+}
+
+// updateProfiles handles the profile manager opened with 'p' on the monitor
+// step. Profiles are saved from, and loaded into, the window counts being
+// edited on that step.
+func (m SetupModel) updateProfiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.profileAction != "" {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Escape):
+			m.profileAction = ""
+			m.profileMsg = ""
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Enter):
+			name := strings.TrimSpace(m.profileInput.Value())
+			pc := &config.Config{Profiles: m.profiles}
+			var err error
+			if m.profileAction == "new" {
+				err = pc.AddProfile(name, monitorsForCounts(m.windowCounts, m.existingMonitors()))
+			} else {
+				err = pc.RenameProfile(m.profiles[m.profileIdx].Name, name)
+			}
+			if err != nil {
+				m.profileMsg = err.Error()
+				return m, nil
+			}
+			m.profiles = pc.Profiles
+			if m.profileAction == "new" {
+				m.profileIdx = len(m.profiles) - 1
+			}
+			m.profileAction = ""
+			m.profileMsg = ""
+			return m, nil
+		default:
+			var cmd tea.Cmd
+			m.profileInput, cmd = m.profileInput.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Escape):
+		m.profileMode = false
+		m.profileMsg = ""
+	case key.Matches(msg, DefaultKeyMap.Up):
+		if m.profileIdx > 0 {
+			m.profileIdx--
+		}
+	case key.Matches(msg, DefaultKeyMap.Down):
+		if m.profileIdx < len(m.profiles)-1 {
+			m.profileIdx++
+		}
+	case key.Matches(msg, DefaultKeyMap.Enter):
+		if len(m.profiles) == 0 {
+			return m, nil
+		}
+		p := m.profiles[m.profileIdx]
+		m.loadedMonitors = config.CopyMonitors(p.Monitors)
+		for i := range m.windowCounts {
+			m.windowCounts[i] = 0
+			if i < len(p.Monitors) {
+				m.windowCounts[i] = p.Monitors[i].WindowCount()
+			}
+		}
+		m.profileMode = false
+		m.profileMsg = "Loaded profile " + p.Name
+	case msg.String() == "n":
+		m.profileAction = "new"
+		m.profileInput = newProfileInput("")
+		m.profileMsg = ""
+		return m, textinput.Blink
+	case msg.String() == "r":
+		if len(m.profiles) == 0 {
+			return m, nil
+		}
+		m.profileAction = "rename"
+		m.profileInput = newProfileInput(m.profiles[m.profileIdx].Name)
+		m.profileMsg = ""
+		return m, textinput.Blink
+	case msg.String() == "d":
+		if len(m.profiles) == 0 {
+			return m, nil
+		}
+		m.profileMsg = "Deleted profile " + m.profiles[m.profileIdx].Name
+		m.profiles = append(m.profiles[:m.profileIdx:m.profileIdx], m.profiles[m.profileIdx+1:]...)
+		if m.profileIdx >= len(m.profiles) && m.profileIdx > 0 {
+			m.profileIdx--
+		}
 	}
 	return m, nil
 }
 
+func newProfileInput(value string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "review"
+	ti.CharLimit = 32
+	ti.Width = 30
+	ti.SetValue(value)
+	ti.Focus()
+	return ti
+}
+
 func (m SetupModel) updateAccounts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, DefaultKeyMap.Enter):
@@ -372,7 +498,10 @@ func (m SetupModel) updateAccounts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.authMessage = ""
 		cmd, args := a.AuthCommand()
 		c := exec.Command(cmd, args...)
-		applyAccountEnv(c, m.keys, a.ID)
+		if err := applyAccountEnv(c, m.existingCfg, m.keys, a.ID); err != nil {
+			m.authMessage = err.Error()
+			return m, nil
+		}
 		accountID := a.ID
 		return m, tea.ExecProcess(c, func(err error) tea.Msg {
 			return authDoneMsg{err: err, accountID: accountID}
@@ -386,7 +515,10 @@ func (m SetupModel) updateAccounts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.authMessage = ""
 		cmd, args := a.InstallCommand()
 		c := exec.Command(cmd, args...)
-		applyAccountEnv(c, m.keys, a.ID)
+		if err := applyAccountEnv(c, m.existingCfg, m.keys, a.ID); err != nil {
+			m.authMessage = err.Error()
+			return m, nil
+		}
 		return m, tea.ExecProcess(c, func(err error) tea.Msg {
 			return installDoneMsg{err: err}
 		})
@@ -532,7 +664,10 @@ func (m SetupModel) updateSetupAddLabel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			cmd, args := newAcct.AuthCommand()
 			c := exec.Command(cmd, args...)
-			applyAccountEnv(c, m.keys, newAcct.ID)
+			if err := applyAccountEnv(c, m.existingCfg, m.keys, newAcct.ID); err != nil {
+				m.authMessage = err.Error()
+				return m, nil
+			}
 			accountID := newAcct.ID
 			m.authMessage = ""
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
@@ -831,7 +966,11 @@ func (m SetupModel) View() string {
 	case stepProjectsRoot:
 		s.WriteString(m.viewProjectsRoot())
 	case stepMonitors:
-		s.WriteString(m.viewMonitors())
+		if m.profileMode {
+			s.WriteString(m.viewProfiles())
+		} else {
+			s.WriteString(m.viewMonitors())
+		}
 	case stepAccounts:
 		if m.addStep != addStepNone {
 			s.WriteString(m.viewSetupAddWizard())
@@ -871,20 +1010,11 @@ func (m SetupModel) viewProjectsRoot() string {
 	var s strings.Builder
 	s.WriteString(RenderSep())
 	s.WriteString("\n")
-	s.WriteString("  " + TitleStyle.Render("Step 1") + " " + SubtitleStyle.Render("Projects Root") + "\n\n")
-	s.WriteString("  " + DimStyle.Render("Directory containing your project folders:") + "\n\n")
-	s.WriteString("  " + m.rootInput.View() + "\n\n")
-
-	// Check if directory exists
-	if m.rootInput.Value() != "" {
-		if _, err := os.Stat(m.rootInput.Value()); os.IsNotExist(err) {
-			s.WriteString("  " + WarningStyle.Render("Directory does not exist — will be created") + "\n")
-		} else {
-			s.WriteString("  " + SuccessStyle.Render("✓ Directory exists") + "\n")
-		}
-	}
-
-	s.WriteString("\n  " + DimStyle.Render("Enter to continue, Esc to go back") + "\n")
+	s.WriteString("  " + TitleStyle.Render("Step 1") + " " + SubtitleStyle.Render("Projects Roots") + "\n\n")
+	s.WriteString("  " + DimStyle.Render("Directories containing your project folders. The picker merges them;") + "\n")
+	s.WriteString("  " + DimStyle.Render("the first is the primary root new folders go into.") + "\n\n")
+	s.WriteString(m.roots.view())
+	s.WriteString("\n  " + DimStyle.Render("Enter to continue, Tab to add a path, Ctrl+X to remove, Esc to go back") + "\n")
 	return s.String()
 }
 
@@ -939,7 +1069,60 @@ func (m SetupModel) viewMonitors() string {
 		}
 	}
 
-	s.WriteString("\n  " + DimStyle.Render("←→ select monitor  ↑↓ adjust windows  Enter to continue") + "\n")
+	if len(m.profiles) > 0 {
+		s.WriteString(fmt.Sprintf("\n  %s  %s\n",
+			DimStyle.Render("Profiles:"),
+			WhiteStyle.Render(strings.Join((&config.Config{Profiles: m.profiles}).ProfileNames(), ", "))))
+	}
+	if m.profileMsg != "" {
+		s.WriteString("\n  " + SuccessStyle.Render(m.profileMsg) + "\n")
+	}
+
+	s.WriteString("\n  " + DimStyle.Render("←→ select monitor  ↑↓ adjust windows  p profiles  Enter to continue") + "\n")
+	return s.String()
+}
+
+func (m SetupModel) viewProfiles() string {
+	var s strings.Builder
+	s.WriteString(RenderSep())
+	s.WriteString("\n")
+	s.WriteString("  " + TitleStyle.Render("Step 2") + " " + SubtitleStyle.Render("Profiles") + "\n\n")
+	s.WriteString("  " + DimStyle.Render("Named arrangements for") + " " + WhiteStyle.Render("qs profile <name>") + "\n\n")
+
+	if len(m.profiles) == 0 {
+		s.WriteString("  " + DimStyle.Render("No profiles yet — press n to save the current layout") + "\n")
+	}
+	for i, p := range m.profiles {
+		counts := make([]string, len(p.Monitors))
+		for j, mc := range p.Monitors {
+			counts[j] = strconv.Itoa(mc.WindowCount())
+		}
+		summary := DimStyle.Render("windows " + strings.Join(counts, "+"))
+		if i == m.profileIdx {
+			s.WriteString(fmt.Sprintf("  %s %s  %s\n", TitleStyle.Render("▸"), SubtitleStyle.Render(p.Name), summary))
+		} else {
+			s.WriteString(fmt.Sprintf("    %s  %s\n", DimStyle.Render(p.Name), summary))
+		}
+	}
+
+	if m.profileAction != "" {
+		title := "New profile from current layout:"
+		if m.profileAction == "rename" {
+			title = "Rename profile:"
+		}
+		s.WriteString("\n  " + DimStyle.Render(title) + "\n\n")
+		s.WriteString("  " + m.profileInput.View() + "\n")
+		if m.profileMsg != "" {
+			s.WriteString("\n  " + ErrorStyle.Render(m.profileMsg) + "\n")
+		}
+		s.WriteString("\n  " + DimStyle.Render("Enter save  Esc cancel") + "\n")
+		return s.String()
+	}
+
+	if m.profileMsg != "" {
+		s.WriteString("\n  " + WarningStyle.Render(m.profileMsg) + "\n")
+	}
+	s.WriteString("\n  " + DimStyle.Render("↑↓ select  Enter load  n new  r rename  d delete  Esc back") + "\n")
 	return s.String()
 }
 
@@ -1140,7 +1323,7 @@ func (m SetupModel) viewStepKeys() string {
 				s.WriteString(fmt.Sprintf("  %s %s  %s\n",
 					prefix,
 					nameStr,
-					DimStyle.Render(config.MaskValue(entry.value))))
+					DimStyle.Render(config.DisplayKeyValue(m.existingCfg, entry.value))))
 			}
 		}
 
@@ -1182,10 +1365,17 @@ func (m SetupModel) viewConfirm() string {
 	s.WriteString("\n")
 	s.WriteString("  " + TitleStyle.Render("Step 5") + " " + SubtitleStyle.Render("Confirm") + "\n\n")
 
-	// Projects root
-	s.WriteString(fmt.Sprintf("  %s  %s\n",
-		DimStyle.Render("Projects:"),
-		WhiteStyle.Render(m.projectsRoot)))
+	// Projects roots
+	for i, r := range m.projectRoots {
+		label := "Projects:"
+		if i > 0 {
+			label = "         "
+		}
+		s.WriteString(fmt.Sprintf("  %s  %s  %s\n",
+			DimStyle.Render(label),
+			WhiteStyle.Render(r.Path),
+			DimStyle.Render(r.Name)))
+	}
 
 	// Monitors
 	for i, count := range m.windowCounts {
@@ -1195,6 +1385,12 @@ func (m SetupModel) viewConfirm() string {
 			count, layout))
 	}
 
+	if len(m.profiles) > 0 {
+		s.WriteString(fmt.Sprintf("  %s  %s\n",
+			DimStyle.Render("Profiles:"),
+			strings.Join((&config.Config{Profiles: m.profiles}).ProfileNames(), ", ")))
+	}
+
 	// Enabled accounts
 	s.WriteString("\n  " + DimStyle.Render("Accounts:") + "\n")
 	for _, a := range m.accounts {
@@ -1233,26 +1429,58 @@ func (m SetupModel) viewDone() string {
 }
 
 func (m SetupModel) buildConfig() *config.Config {
-	monitors := make([]config.MonitorConfig, len(m.windowCounts))
-	for i, count := range m.windowCounts {
+	// Start from the existing config so settings the wizard doesn't edit
+	// are kept, whatever they are
+	cfg := &config.Config{}
+	if m.existingCfg != nil {
+		existing := *m.existingCfg
+		cfg = &existing
+	}
+	cfg.Version = 4
+	cfg.ProjectsRoot = m.projectsRoot
+	cfg.Roots = m.projectRoots
+	cfg.Accounts = m.accounts
+	cfg.Monitors = monitorsForCounts(m.windowCounts, m.existingMonitors())
+	cfg.Profiles = m.profiles
+	if cfg.DefaultAccount == "" {
+		cfg.DefaultAccount = "claude"
+	}
+	if cfg.LastAccount == "" {
+		cfg.LastAccount = "claude"
+	}
+	return cfg
+}
+
+// monitorsForCounts builds a monitor arrangement from per-monitor window
+// counts, keeping the tool/project assignments of windows in existing.
+func monitorsForCounts(counts []int, existing []config.MonitorConfig) []config.MonitorConfig {
+	monitors := make([]config.MonitorConfig, len(counts))
+	for i, count := range counts {
 		windows := make([]config.WindowConfig, count)
 		for j := range windows {
 			windows[j] = config.WindowConfig{Tool: "claude"}
+			if i < len(existing) && j < len(existing[i].Windows) {
+				windows[j] = existing[i].Windows[j]
+			}
 		}
 		monitors[i] = config.MonitorConfig{
 			Layout:  LayoutForCount(count),
 			Windows: windows,
 		}
 	}
+	return monitors
+}
 
-	return &config.Config{
-		Version:        4,
-		ProjectsRoot:   m.projectsRoot,
-		DefaultAccount: "claude",
-		LastAccount:    "claude",
-		Accounts:       m.accounts,
-		Monitors:       monitors,
+// existingMonitors returns the monitor arrangement being edited, if any:
+// the last loaded profile's, or the config's.
+func (m SetupModel) existingMonitors() []config.MonitorConfig {
+	if m.loadedMonitors != nil {
+		return m.loadedMonitors
+	}
+	if m.existingCfg == nil {
+		return nil
 	}
+	return m.existingCfg.Monitors
 }
 
 func LayoutForCount(count int) string {
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bcmister/qs/internal/config"
+	"github.com/bcmister/qs/internal/monitor"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestSetupModel(counts []int) SetupModel {
+	m := NewSetup(&config.Config{}, config.AccountKeys{})
+	m.step = stepMonitors
+	m.monitors = make([]monitor.Monitor, len(counts))
+	m.windowCounts = append([]int{}, counts...)
+	return m
+}
+
+func setupKeys(m SetupModel, keys ...string) SetupModel {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(SetupModel)
+	}
+	return m
+}
+
+func TestSetupProfiles_CreateFromCurrentLayout(t *testing.T) {
+	m := newTestSetupModel([]int{3, 1})
+	m = setupKeys(m, "p", "n", "focus", "enter")
+
+	if len(m.profiles) != 1 || m.profiles[0].Name != "focus" {
+		t.Fatalf("expected profile 'focus', got %+v", m.profiles)
+	}
+	if got := m.profiles[0].Monitors[0].WindowCount(); got != 3 {
+		t.Errorf("expected 3 windows on monitor 1, got %d", got)
+	}
+
+	cfg := m.buildConfig()
+	if cfg.ProfileByName("focus") == nil {
+		t.Error("buildConfig dropped the new profile")
+	}
+}
+
+func TestSetupProfiles_LoadRenameDelete(t *testing.T) {
+	m := newTestSetupModel([]int{1, 1, 1})
+	m.existingCfg = &config.Config{Monitors: []config.MonitorConfig{{
+		Layout:  "full",
+		Windows: []config.WindowConfig{{Tool: "codex", Project: "old"}},
+	}}}
+	review := monitorsForCounts([]int{4, 2, 0}, nil)
+	review[0].Windows[0] = config.WindowConfig{Tool: "gemini", Project: "api", Args: []string{"--yolo"}, Title: "api"}
+	m.profiles = []config.Profile{{Name: "review", Monitors: review}}
+
+	m = setupKeys(m, "p", "enter")
+	if m.profileMode {
+		t.Error("loading a profile should return to the monitor step")
+	}
+	if m.windowCounts[0] != 4 || m.windowCounts[1] != 2 || m.windowCounts[2] != 0 {
+		t.Errorf("expected counts [4 2 0], got %v", m.windowCounts)
+	}
+	monitors := m.buildConfig().Monitors
+	if w := monitors[0].Windows[0]; w.Tool != "gemini" || w.Project != "api" || w.Title != "api" || len(w.Args) != 1 {
+		t.Errorf("loaded profile's window assignment replaced, got %+v", w)
+	}
+	if n := monitors[2].WindowCount(); n != 0 {
+		t.Errorf("expected no windows on monitor 3, got %d", n)
+	}
+	// The config's monitors are a copy, not the profile's own slices
+	monitors[0].Windows[0].Project = "changed"
+	if m.profiles[0].Monitors[0].Windows[0].Project != "api" {
+		t.Error("building the config changed the profile")
+	}
+
+	m = setupKeys(m, "p", "r", "-all", "enter")
+	if m.profiles[0].Name != "review-all" {
+		t.Errorf("expected rename to review-all, got %q", m.profiles[0].Name)
+	}
+
+	m = setupKeys(m, "d")
+	if len(m.profiles) != 0 {
+		t.Errorf("expected profile deleted, got %+v", m.profiles)
+	}
+}
+
+func TestSetupProfiles_InvalidNameRejected(t *testing.T) {
+	m := newTestSetupModel([]int{1})
+	m = setupKeys(m, "p", "n", "bad name", "enter")
+	if len(m.profiles) != 0 {
+		t.Errorf("expected invalid name to be rejected, got %+v", m.profiles)
+	}
+	if m.profileMsg == "" {
+		t.Error("expected an error message for the invalid name")
+	}
+}
+
+func TestSetupBuildConfigKeepsWindowAssignments(t *testing.T) {
+	m := NewSetup(&config.Config{Monitors: []config.MonitorConfig{{
+		Layout:  "vertical",
+		Windows: []config.WindowConfig{{Tool: "codex", Project: "api", Title: "api"}, {Tool: "claude"}},
+	}}}, config.AccountKeys{})
+	m.windowCounts = []int{3}
+
+	cfg := m.buildConfig()
+	windows := cfg.Monitors[0].Windows
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(windows))
+	}
+	if windows[0].Project != "api" || windows[0].Tool != "codex" || windows[0].Title != "api" {
+		t.Errorf("assignment not preserved: %+v", windows[0])
+	}
+	if windows[2].Tool != "claude" || windows[2].Project != "" {
+		t.Errorf("new window should default to claude/picker, got %+v", windows[2])
+	}
+}
+
+func TestSetupBuildConfigKeepsOtherSettings(t *testing.T) {
+	existing := &config.Config{
+		Terminal:       "tmux",
+		DefaultAccount: "codex",
+		Favorites:      []string{"api"},
+		Discovery:      config.DiscoveryConfig{Enabled: true, Depth: 2},
+		SecretHelpers:  map[string]string{"bw": "bw get password {ref}"},
+		Projects:       []config.ProjectSettings{{Project: "api", EnvFiles: []string{".qs.env"}}},
+	}
+	m := NewSetup(existing, config.AccountKeys{})
+	m.windowCounts = []int{1}
+
+	cfg := m.buildConfig()
+	if cfg.Terminal != "tmux" || cfg.DefaultAccount != "codex" || len(cfg.Favorites) != 1 ||
+		!cfg.Discovery.Enabled || cfg.SecretHelpers["bw"] == "" || len(cfg.Projects) != 1 {
+		t.Errorf("settings the wizard doesn't edit were dropped: %+v", cfg)
+	}
+	if cfg == existing || cfg.Version != 4 || len(cfg.Monitors) != 1 {
+		t.Errorf("wizard settings not applied: %+v", cfg)
+	}
+}
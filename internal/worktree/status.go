@@ -0,0 +1,106 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status is a summary of a repository's working tree for the picker.
+type Status struct {
+	Branch     string // current branch, or the short commit hash when detached
+	Dirty      bool   // uncommitted or untracked changes
+	Upstream   bool   // the branch tracks an upstream
+	Ahead      int    // commits not on the upstream
+	Behind     int    // upstream commits not on the branch
+	LastCommit time.Time
+	HasCommits bool
+}
+
+// StatusOf returns the status of the repository rooted at dir. Directories
+// that are only inside a repository, not its top level, are reported as not
+// being repositories so a folder under a versioned projects root doesn't
+// show the root's status.
+func StatusOf(dir string) (Status, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return Status{}, fmt.Errorf("%s is not a git repository", dir)
+	}
+
+	out, err := git(dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, err
+	}
+	st := parseStatus(out)
+
+	if st.HasCommits {
+		if ts, err := git(dir, "log", "-1", "--format=%ct"); err == nil {
+			if sec, err := strconv.ParseInt(ts, 10, 64); err == nil {
+				st.LastCommit = time.Unix(sec, 0)
+			}
+		}
+	}
+	return st, nil
+}
+
+// parseStatus reads the output of git status --porcelain=v2 --branch.
+func parseStatus(out string) Status {
+	var st Status
+	var oid string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.oid "):
+			oid = strings.TrimPrefix(line, "# branch.oid ")
+			st.HasCommits = oid != "(initial)"
+		case strings.HasPrefix(line, "# branch.head "):
+			st.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			st.Upstream = true
+		case strings.HasPrefix(line, "# branch.ab "):
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				st.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				st.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			st.Dirty = true
+		}
+	}
+	if st.Branch == "(detached)" && len(oid) >= 7 {
+		st.Branch = oid[:7]
+	}
+	return st
+}
+
+// Commit is one entry of a repository's log.
+type Commit struct {
+	Hash    string
+	Subject string
+	Time    time.Time
+}
+
+// Log returns the last n commits on the current branch, newest first.
+func Log(dir string, n int) ([]Commit, error) {
+	out, err := git(dir, "log", fmt.Sprintf("-%d", n), "--format=%h%x09%ct%x09%s")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Subject: fields[2]}
+		if sec, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			c.Time = time.Unix(sec, 0)
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
@@ -0,0 +1,69 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := `# branch.oid 1234567890abcdef
+# branch.head feat/login
+# branch.upstream origin/feat/login
+# branch.ab +2 -1
+1 .M N... 100644 100644 100644 abc abc src/main.go
+? notes.txt`
+	st := parseStatus(out)
+	if st.Branch != "feat/login" || !st.Dirty || !st.Upstream || st.Ahead != 2 || st.Behind != 1 || !st.HasCommits {
+		t.Errorf("unexpected status %+v", st)
+	}
+
+	st = parseStatus("# branch.oid 1234567890abcdef\n# branch.head (detached)")
+	if st.Branch != "1234567" || st.Dirty || st.Upstream {
+		t.Errorf("unexpected detached status %+v", st)
+	}
+
+	st = parseStatus("# branch.oid (initial)\n# branch.head main")
+	if st.HasCommits || st.Branch != "main" {
+		t.Errorf("unexpected status for a repo without commits %+v", st)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	repo := initRepo(t)
+
+	st, err := StatusOf(repo)
+	if err != nil {
+		t.Fatalf("StatusOf: %v", err)
+	}
+	if st.Branch == "" || st.Dirty || st.Upstream {
+		t.Errorf("unexpected clean status %+v", st)
+	}
+	if time.Since(st.LastCommit) > time.Hour {
+		t.Errorf("expected a recent last commit, got %v", st.LastCommit)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := StatusOf(repo); !st.Dirty {
+		t.Error("expected untracked file to make the repo dirty")
+	}
+
+	if _, err := StatusOf(filepath.Join(repo, "src")); err == nil {
+		t.Error("expected a subdirectory not to count as a repository")
+	}
+}
+
+func TestLog(t *testing.T) {
+	repo := initRepo(t)
+
+	commits, err := Log(repo, 5)
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "init" || commits[0].Hash == "" || commits[0].Time.IsZero() {
+		t.Errorf("unexpected log %+v", commits)
+	}
+}
@@ -0,0 +1,244 @@
+// Package worktree creates and manages the git worktrees qs launches agents
+// in, so several agents can work on one repository without sharing a
+// working tree.
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Worktree is a git worktree created by qs under the worktree root.
+type Worktree struct {
+	Name   string // <repo>/<dir>, as shown by `qs worktrees`
+	Path   string
+	Repo   string // main repository path, "" if it can't be found
+	Branch string
+	Dirty  bool
+}
+
+// Stale reports whether the worktree's main repository can't be found,
+// because it was deleted or because it was moved or renamed, which `git
+// worktree repair` fixes.
+func (w Worktree) Stale() bool {
+	return w.Repo == ""
+}
+
+// TopLevel returns the root of the git repository containing dir, or an
+// error if dir is not inside a git work tree.
+func TopLevel(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git repository", dir)
+	}
+	return filepath.FromSlash(out), nil
+}
+
+// IsRepo reports whether dir is inside a git work tree.
+func IsRepo(dir string) bool {
+	_, err := TopLevel(dir)
+	return err == nil
+}
+
+// Create adds a worktree for the repository containing dir under
+// root/<repo>/<stamp>, on a fresh branch qs/<repo>-<stamp> started from the
+// current HEAD. It returns the worktree and the directory inside it that
+// corresponds to dir, so launching from a repo subdirectory lands in the
+// same subdirectory.
+func Create(root, dir string) (Worktree, string, error) {
+	top, err := TopLevel(dir)
+	if err != nil {
+		return Worktree{}, "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(top, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = "."
+	}
+
+	repoName := filepath.Base(top)
+	stamp := time.Now().Format("20060102-150405")
+
+	// Agents launched in the same second race for the same name; git refuses
+	// an existing branch or path, so retry with a numeric suffix.
+	var lastErr error
+	for n := 1; n <= 20; n++ {
+		name := stamp
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d", stamp, n)
+		}
+		path := filepath.Join(root, repoName, name)
+		branch := "qs/" + repoName + "-" + name
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return Worktree{}, "", fmt.Errorf("failed to create worktree directory: %w", err)
+		}
+
+		if _, err := git(top, "worktree", "add", "-b", branch, path); err != nil {
+			lastErr = err
+			if !strings.Contains(err.Error(), "already exists") {
+				break
+			}
+			continue
+		}
+		wt := Worktree{
+			Name:   repoName + "/" + name,
+			Path:   path,
+			Repo:   top,
+			Branch: branch,
+		}
+		return wt, filepath.Join(path, rel), nil
+	}
+	return Worktree{}, "", fmt.Errorf("git worktree add failed: %w", lastErr)
+}
+
+// List returns the worktrees under root, sorted by name. Each is a directory
+// root/<repo>/<name> holding a .git file that points back at its repository.
+func List(root string) ([]Worktree, error) {
+	repos, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var worktrees []Worktree
+	for _, repo := range repos {
+		if !repo.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(root, repo.Name()))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			path := filepath.Join(root, repo.Name(), e.Name())
+			gitdir, ok := readGitFile(filepath.Join(path, ".git"))
+			if !ok {
+				continue
+			}
+			wt := Worktree{
+				Name: repo.Name() + "/" + e.Name(),
+				Path: path,
+			}
+			// gitdir is <repo>/.git/worktrees/<id>
+			if _, err := os.Stat(gitdir); err == nil {
+				wt.Repo = filepath.Dir(filepath.Dir(filepath.Dir(gitdir)))
+				wt.Branch, _ = git(path, "rev-parse", "--abbrev-ref", "HEAD")
+				status, _ := git(path, "status", "--porcelain")
+				wt.Dirty = status != ""
+			}
+			worktrees = append(worktrees, wt)
+		}
+	}
+
+	sort.Slice(worktrees, func(i, j int) bool { return worktrees[i].Name < worktrees[j].Name })
+	return worktrees, nil
+}
+
+// Find returns the worktree under root with the given name (<repo>/<dir>),
+// or the unique one whose directory name matches.
+func Find(root, name string) (Worktree, error) {
+	worktrees, err := List(root)
+	if err != nil {
+		return Worktree{}, err
+	}
+	var matches []Worktree
+	for _, wt := range worktrees {
+		if wt.Name == name {
+			return wt, nil
+		}
+		if filepath.Base(wt.Path) == name {
+			matches = append(matches, wt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Worktree{}, fmt.Errorf("worktree %q not found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return Worktree{}, fmt.Errorf("worktree %q is ambiguous, use <repo>/%s", name, name)
+	}
+}
+
+// Remove deletes a worktree and, if it has been merged, its branch.
+// Dirty worktrees are only removed when force is set, and so are stale
+// ones, whose changes can't be checked.
+func Remove(wt Worktree, force bool) error {
+	if wt.Stale() {
+		if !force {
+			return fmt.Errorf("worktree %s is stale (use --force)", wt.Name)
+		}
+		return os.RemoveAll(wt.Path)
+	}
+	if wt.Dirty && !force {
+		return fmt.Errorf("worktree %s has uncommitted changes (use --force)", wt.Name)
+	}
+
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	if _, err := git(wt.Repo, append(args, wt.Path)...); err != nil {
+		return err
+	}
+	// Best effort: -d keeps branches with unmerged work
+	if strings.HasPrefix(wt.Branch, "qs/") {
+		git(wt.Repo, "branch", "-d", wt.Branch)
+	}
+
+	// Drop the per-repo directory once its last worktree is gone
+	os.Remove(filepath.Dir(wt.Path))
+	return nil
+}
+
+// readGitFile parses a worktree's .git file ("gitdir: <path>").
+func readGitFile(path string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return "", false
+	}
+	gitdir, ok := strings.CutPrefix(scanner.Text(), "gitdir: ")
+	if !ok {
+		return "", false
+	}
+	gitdir = filepath.FromSlash(strings.TrimSpace(gitdir))
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(filepath.Dir(path), gitdir)
+	}
+	return gitdir, true
+}
+
+// git runs a git command in dir and returns its trimmed stdout.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
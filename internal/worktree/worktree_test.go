@@ -0,0 +1,167 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with one commit and a src/ subdirectory.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := filepath.Join(t.TempDir(), "api")
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=qs", "-c", "user.email=qs@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestCreateListRemove(t *testing.T) {
+	repo := initRepo(t)
+	root := filepath.Join(t.TempDir(), "worktrees")
+
+	wt, launchDir, err := Create(root, filepath.Join(repo, "src"))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(wt.Name, "api/") || !strings.HasPrefix(wt.Branch, "qs/api-") {
+		t.Errorf("unexpected worktree %+v", wt)
+	}
+	if filepath.Base(launchDir) != "src" {
+		t.Errorf("expected launch dir in src/, got %s", launchDir)
+	}
+	if _, err := os.Stat(filepath.Join(launchDir, "main.go")); err != nil {
+		t.Errorf("worktree not checked out: %v", err)
+	}
+
+	// A second worktree in the same second must not collide
+	wt2, _, err := Create(root, repo)
+	if err != nil {
+		t.Fatalf("second Create failed: %v", err)
+	}
+	if wt2.Branch == wt.Branch || wt2.Path == wt.Path {
+		t.Errorf("second worktree collided with the first: %+v", wt2)
+	}
+
+	if err := os.WriteFile(filepath.Join(wt2.Path, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := List(root)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(list))
+	}
+	byName := map[string]Worktree{}
+	for _, w := range list {
+		byName[w.Name] = w
+	}
+	if got := byName[wt.Name]; got.Dirty || got.Branch != wt.Branch || got.Stale() {
+		t.Errorf("worktree 1: got %+v", got)
+	}
+	if got := byName[wt2.Name]; !got.Dirty {
+		t.Errorf("worktree 2 should be dirty: %+v", got)
+	}
+
+	if err := Remove(byName[wt2.Name], false); err == nil {
+		t.Error("expected removing a dirty worktree without force to fail")
+	}
+	if err := Remove(byName[wt.Name], false); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := Remove(byName[wt2.Name], true); err != nil {
+		t.Fatalf("forced Remove failed: %v", err)
+	}
+
+	list, _ = List(root)
+	if len(list) != 0 {
+		t.Errorf("expected no worktrees after remove, got %+v", list)
+	}
+	if out, _ := exec.Command("git", "-C", repo, "branch", "--list", wt.Branch).Output(); strings.TrimSpace(string(out)) != "" {
+		t.Errorf("merged branch %s was not deleted", wt.Branch)
+	}
+}
+
+func TestRemoveStaleNeedsForce(t *testing.T) {
+	repo := initRepo(t)
+	root := filepath.Join(t.TempDir(), "worktrees")
+	wt, _, err := Create(root, repo)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt.Path, "work.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Moving the repository leaves the worktree's gitdir dangling
+	if err := os.Rename(repo, repo+"-moved"); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := Find(root, wt.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stale.Stale() {
+		t.Fatalf("expected a stale worktree, got %+v", stale)
+	}
+	if err := Remove(stale, false); err == nil {
+		t.Error("expected removing a stale worktree without force to fail")
+	}
+	if _, err := os.Stat(filepath.Join(wt.Path, "work.txt")); err != nil {
+		t.Fatalf("stale worktree was deleted: %v", err)
+	}
+	if err := Remove(stale, true); err != nil {
+		t.Fatalf("forced Remove failed: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Errorf("forced Remove left %s behind", wt.Path)
+	}
+}
+
+func TestFind(t *testing.T) {
+	repo := initRepo(t)
+	root := filepath.Join(t.TempDir(), "worktrees")
+	wt, _, err := Create(root, repo)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for _, name := range []string{wt.Name, filepath.Base(wt.Path)} {
+		got, err := Find(root, name)
+		if err != nil || got.Path != wt.Path {
+			t.Errorf("Find(%q) = %+v, %v", name, got, err)
+		}
+	}
+	if _, err := Find(root, "missing"); err == nil {
+		t.Error("expected error for unknown worktree")
+	}
+}
+
+func TestCreateOutsideRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if _, _, err := Create(t.TempDir(), t.TempDir()); err == nil {
+		t.Error("expected error creating a worktree outside a git repository")
+	}
+}